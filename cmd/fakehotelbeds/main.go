@@ -0,0 +1,82 @@
+// Command fakehotelbeds runs a local stand-in for the Hotelbeds Booking and Content APIs.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/fakehotelbeds"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/logging"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	seedFile := flag.String("seed", "", "seed file with the hotel inventory (defaults to a built-in inventory)")
+	apiKey := flag.String("api-key", "fake-key", "accepted Api-key header")
+	secret := flag.String("secret", "fake-secret", "secret used to verify X-Signature")
+	faultsFile := flag.String("faults", "", "JSON file with the initial fault script")
+	flag.Parse()
+
+	slog.SetDefault(logging.NewLogger(os.Stdout, slog.LevelDebug))
+
+	if err := run(*addr, *seedFile, *apiKey, *secret, *faultsFile); err != nil {
+		slog.Error("fake hotelbeds failed", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+func run(addr, seedFile, apiKey, secret, faultsFile string) error {
+	seed := fakehotelbeds.DefaultSeed()
+	if seedFile != "" {
+		var err error
+		if seed, err = fakehotelbeds.LoadSeed(seedFile); err != nil {
+			return err
+		}
+	}
+
+	var faults []fakehotelbeds.Fault
+	if faultsFile != "" {
+		content, err := os.ReadFile(faultsFile)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(content, &faults); err != nil {
+			return err
+		}
+	}
+
+	fake := fakehotelbeds.New(fakehotelbeds.Options{APIKey: apiKey, Secret: secret, Seed: seed, Faults: faults})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: fake.Handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("fake hotelbeds listening", slog.String("addr", ln.Addr().String()), slog.Int("hotels", len(seed.Hotels)))
+
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	ErrUnknownKey  = errors.New("invalid API key")
+	ErrDisabledKey = errors.New("API key is disabled")
+)
+
+// KeyStore resolves liteAPI keys to the tenant that owns them
+type KeyStore interface {
+	Lookup(apiKey string) (Tenant, error)
+}
+
+// keyFile represents the keystore file; keys are stored as SHA-256 hashes, never in plain text
+type keyFile struct {
+	Keys []keyEntry `json:"keys"`
+}
+
+type keyEntry struct {
+	Hash     string `json:"hash"`
+	Disabled bool   `json:"disabled"`
+	Tenant   Tenant `json:"tenant"`
+}
+
+type FileKeyStore struct {
+	keys map[string]keyEntry
+}
+
+// NewFileKeyStore loads and validates the keystore file at path
+func NewFileKeyStore(path string) (*FileKeyStore, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keystore: %w", err)
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore: %w", err)
+	}
+
+	store := &FileKeyStore{keys: make(map[string]keyEntry, len(file.Keys))}
+	for i, entry := range file.Keys {
+		hash := strings.ToLower(strings.TrimPrefix(entry.Hash, hashPrefix))
+		if len(hash) != sha256.Size*2 {
+			return nil, fmt.Errorf("keystore entry %d: hash must be a hex encoded SHA-256 digest", i)
+		}
+
+		if entry.Tenant.ID == "" {
+			return nil, fmt.Errorf("keystore entry %d: tenant id is required", i)
+		}
+
+		if _, ok := store.keys[hash]; ok {
+			return nil, fmt.Errorf("keystore entry %d: duplicate key hash", i)
+		}
+
+		store.keys[hash] = entry
+	}
+
+	return store, nil
+}
+
+// Lookup returns the tenant owning apiKey
+func (s *FileKeyStore) Lookup(apiKey string) (Tenant, error) {
+	entry, ok := s.keys[hashKey(apiKey)]
+	if !ok {
+		return Tenant{}, ErrUnknownKey
+	}
+
+	if entry.Disabled {
+		return Tenant{}, ErrDisabledKey
+	}
+
+	return entry.Tenant, nil
+}
+
+const hashPrefix = "sha256:"
+
+// HashKey returns the value to store in the keystore for apiKey
+func HashKey(apiKey string) string {
+	return hashPrefix + hashKey(apiKey)
+}
+
+func hashKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKeystore(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestFileKeyStore(t *testing.T) {
+	path := writeKeystore(t, `{"keys":[
+		{"hash":"`+HashKey("active-key")+`","tenant":{"id":"acme","name":"Acme","supplierConfig":"acme-config","markupPercent":10}},
+		{"hash":"`+hashKey("revoked-key")+`","disabled":true,"tenant":{"id":"old"}}
+	]}`)
+
+	store, err := NewFileKeyStore(path)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		apiKey      string
+		expectedID  string
+		expectedErr error
+	}{
+		{name: "Active key", apiKey: "active-key", expectedID: "acme"},
+		{name: "Disabled key", apiKey: "revoked-key", expectedErr: ErrDisabledKey},
+		{name: "Unknown key", apiKey: "other-key", expectedErr: ErrUnknownKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tenant, err := store.Lookup(tt.apiKey)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedID, tenant.ID)
+			assert.Equal(t, "acme-config", tenant.SupplierConfig)
+			assert.Equal(t, 10.0, tenant.MarkupPercent)
+		})
+	}
+}
+
+func TestNewFileKeyStore_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{name: "Invalid JSON", content: "{", errContains: "failed to parse keystore"},
+		{name: "Plain text key", content: `{"keys":[{"hash":"my-key","tenant":{"id":"acme"}}]}`, errContains: "SHA-256"},
+		{name: "Missing tenant", content: `{"keys":[{"hash":"` + HashKey("k") + `"}]}`, errContains: "tenant id is required"},
+		{name: "Duplicate key", content: `{"keys":[{"hash":"` + HashKey("k") + `","tenant":{"id":"a"}},{"hash":"` + HashKey("k") + `","tenant":{"id":"b"}}]}`, errContains: "duplicate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFileKeyStore(writeKeystore(t, tt.content))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+
+	_, err := NewFileKeyStore("/does/not/exist.json")
+	assert.Error(t, err)
+}
+
+func TestTenantContext(t *testing.T) {
+	_, ok := TenantFromContext(context.Background())
+	assert.False(t, ok)
+
+	tenant, ok := TenantFromContext(WithTenant(context.Background(), Tenant{ID: "acme"}))
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant.ID)
+}
@@ -0,0 +1,29 @@
+package auth
+
+import "context"
+
+// Tenant represents the liteAPI customer identified by an API key
+type Tenant struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	SupplierConfig string  `json:"supplierConfig"`
+	MarkupPercent  float64 `json:"markupPercent"`
+	// RequestsPerSecond and Burst override the default per-tenant rate limit when set
+	RequestsPerSecond float64 `json:"requestsPerSecond"`
+	Burst             int     `json:"burst"`
+	// ResponseProfile is the response profile used when a request does not ask for one
+	ResponseProfile string `json:"responseProfile,omitempty"`
+}
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant carried by ctx, if any
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantKey{}).(Tenant)
+	return tenant, ok
+}
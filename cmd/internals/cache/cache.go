@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Flush()
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+type MemoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]entry
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+}
+
+func NewMemoryCache(ttl time.Duration, maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		entries:    map[string]entry{},
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		now:        time.Now,
+	}
+}
+
+// Get returns the cached value for key if it has not expired
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if m.now().After(e.expiresAt) {
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	return e.value, true
+}
+
+// Set stores value under key, evicting expired entries and then the entry closest to expiry when full
+func (m *MemoryCache) Set(key string, value []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.entries[key]; !ok && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		m.evict()
+	}
+
+	m.entries[key] = entry{
+		value:     value,
+		expiresAt: m.now().Add(m.ttl),
+	}
+}
+
+// Flush drops every entry
+func (m *MemoryCache) Flush() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.entries = map[string]entry{}
+}
+
+func (m *MemoryCache) evict() {
+	now := m.now()
+	var oldestKey string
+	var oldest time.Time
+
+	for k, e := range m.entries {
+		if now.After(e.expiresAt) {
+			delete(m.entries, k)
+			continue
+		}
+
+		if oldestKey == "" || e.expiresAt.Before(oldest) {
+			oldestKey, oldest = k, e.expiresAt
+		}
+	}
+
+	if len(m.entries) >= m.maxEntries && oldestKey != "" {
+		delete(m.entries, oldestKey)
+	}
+}
@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewMemoryCache(time.Minute, 2)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", []byte("1"))
+	now = now.Add(time.Second)
+	c.Set("b", []byte("2"))
+
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	// Adding a third entry evicts the one closest to expiry
+	now = now.Add(time.Second)
+	c.Set("c", []byte("3"))
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+
+	// Entries expire after the TTL
+	now = now.Add(2 * time.Minute)
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+
+	c.Set("d", []byte("4"))
+	c.Flush()
+	_, ok = c.Get("d")
+	assert.False(t, ok)
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+var ErrCircuitOpen = errors.New("hotelbeds circuit breaker is open")
+
+// CircuitBreaker stops calling the supplier after consecutive failures and
+// lets a single probe through once the open timeout has elapsed
+type CircuitBreaker struct {
+	mu          sync.Mutex
+	state       CircuitState
+	failures    int
+	threshold   int
+	openTimeout time.Duration
+	openedAt    time.Time
+	// probing is set while the half-open probe is in flight, since probeAt
+	probing bool
+	probeAt time.Time
+	now     func() time.Time
+}
+
+// NewCircuitBreaker creates a breaker; a zero failure threshold disables it
+func NewCircuitBreaker(cfg config.CircuitBreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		state:       CircuitClosed,
+		threshold:   cfg.FailureThreshold,
+		openTimeout: cfg.OpenTimeout.Duration,
+		now:         time.Now,
+	}
+}
+
+// Allow reports whether a call may be made, moving an expired open circuit to half-open. While half-open
+// only the probe is allowed until Success, Failure or Release settles it; a probe that never settles is
+// replaced after the open timeout.
+func (b *CircuitBreaker) Allow() error {
+	if b == nil || b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.startProbe()
+		return nil
+	case CircuitHalfOpen:
+		if b.probing && b.now().Sub(b.probeAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.startProbe()
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *CircuitBreaker) startProbe() {
+	b.probing = true
+	b.probeAt = b.now()
+}
+
+// Success closes the circuit and resets the failure count
+func (b *CircuitBreaker) Success() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = CircuitClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Release ends a call that says nothing about the supplier's health, e.g. a rejected request, so a
+// half-open circuit lets the next call probe
+func (b *CircuitBreaker) Release() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Failure counts a failed call and opens the circuit once the threshold is reached
+func (b *CircuitBreaker) Failure() {
+	if b == nil || b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// State returns the current state of the circuit
+func (b *CircuitBreaker) State() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
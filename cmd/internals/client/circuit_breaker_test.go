@@ -0,0 +1,112 @@
+package client
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(config.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      config.Duration{Duration: time.Minute},
+	})
+	breaker.now = func() time.Time { return now }
+
+	assert.NoError(t, breaker.Allow())
+	breaker.Failure()
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	// Reaching the threshold opens the circuit
+	breaker.Failure()
+	assert.Equal(t, CircuitOpen, breaker.State())
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	// After the open timeout a probe is let through
+	now = now.Add(time.Minute)
+	assert.NoError(t, breaker.Allow())
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+
+	// A failed probe re-opens the circuit immediately
+	breaker.Failure()
+	assert.Equal(t, CircuitOpen, breaker.State())
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	assert.NoError(t, breaker.Allow())
+	breaker.Success()
+	assert.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreaker_HalfOpenSingleProbe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	breaker := NewCircuitBreaker(config.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      config.Duration{Duration: time.Minute},
+	})
+	breaker.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+
+	breaker.Failure()
+	mu.Lock()
+	now = now.Add(time.Minute)
+	mu.Unlock()
+
+	// Only one of the concurrent callers probes the half-open circuit
+	var allowed, rejected atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if breaker.Allow() == nil {
+				allowed.Add(1)
+			} else {
+				rejected.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), allowed.Load())
+	assert.Equal(t, int32(19), rejected.Load())
+	assert.Equal(t, CircuitHalfOpen, breaker.State())
+
+	// A probe that says nothing about the supplier lets the next caller probe
+	breaker.Release()
+	assert.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	// A probe that never settles is replaced after the open timeout
+	mu.Lock()
+	now = now.Add(time.Minute)
+	mu.Unlock()
+	assert.NoError(t, breaker.Allow())
+
+	breaker.Success()
+	assert.Equal(t, CircuitClosed, breaker.State())
+	assert.NoError(t, breaker.Allow())
+	assert.NoError(t, breaker.Allow())
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := NewCircuitBreaker(config.CircuitBreakerConfig{})
+	for i := 0; i < 10; i++ {
+		breaker.Failure()
+	}
+	assert.NoError(t, breaker.Allow())
+	assert.Equal(t, CircuitClosed, breaker.State())
+
+	var nilBreaker *CircuitBreaker
+	assert.NoError(t, nilBreaker.Allow())
+	assert.Equal(t, CircuitClosed, nilBreaker.State())
+}
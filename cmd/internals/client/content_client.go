@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+)
+
+// ContentClient reads hotel static content from the Hotelbeds Content API
+type ContentClient interface {
+	// Hotels returns the content of the given hotels, or of the hotels in positions from to to
+	// of the whole portfolio when codes is empty
+	Hotels(ctx context.Context, codes []int, from, to int) ([]byte, error)
+}
+
+type HotelBedsContentClientImpl struct {
+	api      *HotelBedsClientImpl
+	language string
+}
+
+// NewHotelBedsContentClient creates the Content API client; it shares the credentials and signing of the
+// booking client, and should share its limiter, as content calls count against the same supplier caps
+func NewHotelBedsContentClient(cfg config.HotelBedsConfig, content config.ContentConfig, breaker *CircuitBreaker, limiter *SupplierLimiter) ContentClient {
+	return &HotelBedsContentClientImpl{
+		api:      NewHotelBedsClient(cfg, breaker, limiter).(*HotelBedsClientImpl),
+		language: content.Language,
+	}
+}
+
+func (c *HotelBedsContentClientImpl) Hotels(ctx context.Context, codes []int, from, to int) ([]byte, error) {
+	query := url.Values{}
+	query.Set("fields", "all")
+	query.Set("language", c.language)
+	query.Set("useSecondaryLanguage", "false")
+	query.Set("from", strconv.Itoa(from))
+	query.Set("to", strconv.Itoa(to))
+	if len(codes) > 0 {
+		values := make([]string, len(codes))
+		for i, code := range codes {
+			values[i] = strconv.Itoa(code)
+		}
+		query.Set("codes", strings.Join(values, ","))
+	}
+
+	url := fmt.Sprintf("%s/hotel-content-api/1.0/hotels?%s", c.api.baseURL, query.Encode())
+
+	return c.api.call(ctx, "HotelBedsContentClient.Hotels", http.MethodGet, url, nil)
+}
@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentClient_Hotels(t *testing.T) {
+	tests := []struct {
+		name          string
+		codes         []int
+		expectedQuery map[string]string
+	}{
+		{
+			name:          "Portfolio page",
+			expectedQuery: map[string]string{"from": "1", "to": "100", "codes": "", "language": "ENG", "fields": "all"},
+		},
+		{
+			name:          "Selected hotels",
+			codes:         []int{1234, 5678},
+			expectedQuery: map[string]string{"from": "1", "to": "100", "codes": "1234,5678", "language": "ENG", "fields": "all"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				assert.Equal(t, "/hotel-content-api/1.0/hotels", r.URL.Path)
+				assert.NotEmpty(t, r.Header.Get(util.HeaderSignature))
+				for name, value := range tt.expectedQuery {
+					assert.Equal(t, value, r.URL.Query().Get(name), name)
+				}
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"from":1,"to":100,"total":0,"hotels":[]}`))
+			}))
+			defer mockServer.Close()
+
+			contentClient := NewHotelBedsContentClient(
+				config.HotelBedsConfig{
+					BaseURL:   mockServer.URL,
+					APIKey:    "test-key",
+					APISecret: "test-secret",
+					Timeout:   config.Duration{Duration: time.Second * 10},
+				},
+				config.Default().Content,
+				nil,
+				nil,
+			)
+
+			response, err := contentClient.Hotels(context.Background(), tt.codes, 1, 100)
+			assert.NoError(t, err)
+			assert.Contains(t, string(response), `"total":0`)
+		})
+	}
+}
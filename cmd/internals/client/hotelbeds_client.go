@@ -3,19 +3,28 @@ package client
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"os"
 	"time"
 
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/logging"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type HotelBedsClient interface {
-	SearchHotels(request []byte) ([]byte, error)
+	SearchHotels(ctx context.Context, request []byte) ([]byte, error)
+	Status(ctx context.Context) error
 }
 
 type HotelBedsClientImpl struct {
@@ -23,52 +32,158 @@ type HotelBedsClientImpl struct {
 	httpClient *http.Client
 	apiKey     string
 	apiSecret  string
+	breaker    *CircuitBreaker
+	limiter    *SupplierLimiter
 }
 
-func NewHotelBedsClient() HotelBedsClient {
+// NewHotelBedsClient creates the supplier client; a nil breaker never opens and a nil limiter never waits.
+// Clients of the same Hotelbeds account must share the limiter so the supplier caps hold across them.
+func NewHotelBedsClient(cfg config.HotelBedsConfig, breaker *CircuitBreaker, limiter *SupplierLimiter) HotelBedsClient {
 	return &HotelBedsClientImpl{
-		baseURL:   os.Getenv("HOTEL_BEDS_BASE_URL"),
-		apiKey:    os.Getenv("HOTEL_BEDS_API_KEY"),
-		apiSecret: os.Getenv("HOTEL_BEDS_SECRET"),
+		baseURL:   cfg.BaseURL,
+		apiKey:    cfg.APIKey,
+		apiSecret: cfg.APISecret,
+		breaker:   breaker,
+		limiter:   limiter,
 		httpClient: &http.Client{
-			Timeout: time.Second * 10,
+			Timeout: cfg.Timeout.Duration,
 		},
 	}
 }
 
-func (c *HotelBedsClientImpl) SearchHotels(request []byte) (response []byte, err error) {
+func (c *HotelBedsClientImpl) SearchHotels(ctx context.Context, request []byte) (response []byte, err error) {
 
 	// Create the request URL with the base URL
 	url := fmt.Sprintf("%s/hotel-api/1.0/hotels", c.baseURL)
 
-	// Create new POST request with the JSON body
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(request))
+	return c.call(ctx, "HotelBedsClient.SearchHotels", http.MethodPost, url, request)
+}
+
+// call sends a request in a client span, through the circuit breaker
+func (c *HotelBedsClientImpl) call(ctx context.Context, spanName, method, url string, request []byte) (response []byte, err error) {
+	ctx, span := telemetry.StartSpan(
+		ctx,
+		spanName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(method),
+			semconv.URLFull(url),
+		),
+	)
+	defer func() {
+		if err != nil {
+			telemetry.RecordError(span, err)
+			slog.ErrorContext(ctx, "hotelbeds request failed", slog.String("url", url), slog.String("error", err.Error()))
+		}
+		span.End()
+	}()
+
+	if err = c.breaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	var transient bool
+	response, transient, err = c.send(ctx, method, url, request)
+	c.recordOutcome(err, transient)
+
+	return response, err
+}
+
+// recordOutcome feeds the circuit breaker; only transient failures count against the supplier
+func (c *HotelBedsClientImpl) recordOutcome(err error, transient bool) {
+	switch {
+	case err == nil:
+		c.breaker.Success()
+	case transient:
+		c.breaker.Failure()
+	default:
+		c.breaker.Release()
+	}
+}
+
+// Status calls the Hotelbeds status endpoint to check the supplier is reachable and accepts our credentials
+func (c *HotelBedsClientImpl) Status(ctx context.Context) error {
+	url := fmt.Sprintf("%s/hotel-api/1.0/status", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err = c.setHeaders(req); err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return response, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to make API request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// send sends a single request and reports whether a failure is transient, i.e. a sign the supplier is unhealthy
+func (c *HotelBedsClientImpl) send(ctx context.Context, method, url string, request []byte) (response []byte, transient bool, err error) {
+	// Respect the supplier's rate and quota before anything is sent
+	if err = c.limiter.Wait(ctx); err != nil {
+		return response, false, err
+	}
+
+	start := time.Now()
+
+	// Create the request with the JSON body, if any
+	var body io.Reader
+	if request != nil {
+		body = bytes.NewBuffer(request)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return response, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Set headers
 	err = c.setHeaders(req)
 	if err != nil {
-		return response, fmt.Errorf("failed to create request: %w", err)
+		return response, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	// Propagate the trace context to the supplier
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	slog.DebugContext(ctx, "sending hotelbeds request", slog.String("url", url), logging.Headers("headers", req.Header))
+
 	// Make the request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return response, fmt.Errorf("failed to make API request: %w", err)
+		return response, ctx.Err() == nil, fmt.Errorf("failed to make API request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	slog.InfoContext(
+		ctx,
+		"hotelbeds response received",
+		slog.String("url", url),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", time.Since(start)),
+	)
+
 	if resp.StatusCode != http.StatusOK {
-		return response, fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
+		transient = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		return response, transient, fmt.Errorf("API returned non-200 status code: %d", resp.StatusCode)
 	}
 
 	var reader io.ReadCloser
 	if resp.Header.Get("Content-Encoding") == "gzip" {
 		reader, err = gzip.NewReader(resp.Body)
 		if err != nil {
-			return response, fmt.Errorf("failed to create gzip reader: %w", err)
+			return response, false, fmt.Errorf("failed to create gzip reader: %w", err)
 		}
 		defer reader.Close()
 	} else {
@@ -78,10 +193,10 @@ func (c *HotelBedsClientImpl) SearchHotels(request []byte) (response []byte, err
 	// Read the response body into a string
 	response, err = io.ReadAll(reader)
 	if err != nil {
-		return response, fmt.Errorf("failed to read response body: %w", err)
+		return response, true, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return response, nil
+	return response, false, nil
 }
 
 func (c *HotelBedsClientImpl) setHeaders(req *http.Request) error {
@@ -100,9 +215,9 @@ func (c *HotelBedsClientImpl) setHeaders(req *http.Request) error {
 }
 
 func (c *HotelBedsClientImpl) generateSignature() (string, error) {
-	// Check if environment variables are set
+	// Check if the credentials are configured
 	if c.apiKey == "" || c.apiSecret == "" {
-		return "", fmt.Errorf("environment variables 'HOTEL_BEDS_API_KEY' and 'HOTEL_BEDS_SECRET' are required")
+		return "", fmt.Errorf("hotelbeds API key and secret are required")
 	}
 
 	// Generate the current UTC timestamp in seconds
@@ -1,24 +1,50 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/logging"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	telemetrymocks "github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry/mocks"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func TestNewHotelBedsClient(t *testing.T) {
-	// Setup test environment variables
-	t.Setenv("HOTEL_BEDS_BASE_URL", "http://test.com")
-	t.Setenv("HOTEL_BEDS_API_KEY", "test-key")
-	t.Setenv("HOTEL_BEDS_SECRET", "test-secret")
+func newTestClient(baseURL string) HotelBedsClient {
+	return NewHotelBedsClient(
+		config.HotelBedsConfig{
+			BaseURL:   baseURL,
+			APIKey:    "test-key",
+			APISecret: "test-secret",
+			Timeout:   config.Duration{Duration: time.Second * 10},
+		},
+		nil,
+		nil,
+	)
+}
 
-	client := NewHotelBedsClient()
+func TestNewHotelBedsClient(t *testing.T) {
+	client := NewHotelBedsClient(
+		config.HotelBedsConfig{
+			BaseURL:   "http://test.com",
+			APIKey:    "test-key",
+			APISecret: "test-secret",
+			Timeout:   config.Duration{Duration: time.Second * 10},
+		},
+		nil,
+		nil,
+	)
 	impl, ok := client.(*HotelBedsClientImpl)
 	assert.True(t, ok)
 	assert.Equal(t, "http://test.com", impl.baseURL)
@@ -56,11 +82,7 @@ func TestSearchHotels(t *testing.T) {
 	defer mockServer.Close()
 
 	// Setup test client
-	t.Setenv("HOTEL_BEDS_BASE_URL", mockServer.URL)
-	t.Setenv("HOTEL_BEDS_API_KEY", "test-key")
-	t.Setenv("HOTEL_BEDS_SECRET", "test-secret")
-
-	client := NewHotelBedsClient()
+	client := newTestClient(mockServer.URL)
 
 	// Test search request
 	request := &dto.HotelBedsSearchRequest{
@@ -80,7 +102,7 @@ func TestSearchHotels(t *testing.T) {
 	requestBytes, err := json.Marshal(request)
 	assert.NoError(t, err)
 
-	response, err := client.SearchHotels(requestBytes)
+	response, err := client.SearchHotels(context.Background(), requestBytes)
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
 
@@ -104,11 +126,7 @@ func TestSearchHotels_Error(t *testing.T) {
 	defer mockServer.Close()
 
 	// Setup test client
-	t.Setenv("HOTEL_BEDS_BASE_URL", mockServer.URL)
-	t.Setenv("HOTEL_BEDS_API_KEY", "test-key")
-	t.Setenv("HOTEL_BEDS_SECRET", "test-secret")
-
-	client := NewHotelBedsClient()
+	client := newTestClient(mockServer.URL)
 
 	requestBytes, err := json.Marshal(&dto.HotelBedsSearchRequest{
 		Stay: dto.Stay{
@@ -118,7 +136,7 @@ func TestSearchHotels_Error(t *testing.T) {
 	})
 	assert.NoError(t, err)
 
-	response, err := client.SearchHotels(requestBytes)
+	response, err := client.SearchHotels(context.Background(), requestBytes)
 	assert.Error(t, err)
 	assert.Nil(t, response)
 	assert.Contains(t, err.Error(), "API returned non-200 status code")
@@ -143,7 +161,7 @@ func TestGenerateSignature(t *testing.T) {
 			name:        "Missing credentials",
 			client:      &HotelBedsClientImpl{},
 			wantErr:     true,
-			errContains: "environment variables",
+			errContains: "API key and secret are required",
 		},
 	}
 
@@ -161,3 +179,145 @@ func TestGenerateSignature(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchHotels_Tracing(t *testing.T) {
+	recorder := telemetrymocks.NewSpanRecorder()
+
+	var traceparent string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		json.NewEncoder(w).Encode(dto.HotelbedsResponse{})
+	}))
+	defer mockServer.Close()
+
+	ctx, parent := telemetry.StartSpan(context.Background(), "parent")
+	_, err := newTestClient(mockServer.URL).SearchHotels(ctx, []byte("{}"))
+	parent.End()
+	assert.NoError(t, err)
+
+	span := telemetrymocks.SpanByName(recorder, "HotelBedsClient.SearchHotels")
+	assert.NotNil(t, span)
+	assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	assert.Contains(t, traceparent, span.SpanContext().SpanID().String())
+}
+
+func TestSearchHotels_Logging(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(dto.HotelbedsResponse{})
+	}))
+	defer mockServer.Close()
+
+	buf := &bytes.Buffer{}
+	previous := slog.Default()
+	slog.SetDefault(logging.NewLogger(buf, slog.LevelDebug))
+	defer slog.SetDefault(previous)
+
+	ctx := logging.WithRequestID(context.Background(), "req-42")
+	_, err := newTestClient(mockServer.URL).SearchHotels(ctx, []byte("{}"))
+	assert.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Contains(t, line, `"request_id":"req-42"`)
+	}
+	assert.NotContains(t, buf.String(), "test-key")
+}
+
+func TestSearchHotels_CircuitBreaker(t *testing.T) {
+	calls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	breaker := NewCircuitBreaker(config.CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      config.Duration{Duration: time.Minute},
+	})
+	client := NewHotelBedsClient(
+		config.HotelBedsConfig{
+			BaseURL:   mockServer.URL,
+			APIKey:    "test-key",
+			APISecret: "test-secret",
+			Timeout:   config.Duration{Duration: time.Second},
+		},
+		breaker,
+		nil,
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := client.SearchHotels(context.Background(), []byte("{}"))
+		assert.Error(t, err)
+	}
+
+	_, err := client.SearchHotels(context.Background(), []byte("{}"))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, CircuitOpen, breaker.State())
+}
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Supplier up", status: http.StatusOK},
+		{name: "Supplier down", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/hotel-api/1.0/status", r.URL.Path)
+				assert.NotEmpty(t, r.Header.Get(util.HeaderSignature))
+				w.WriteHeader(tt.status)
+			}))
+			defer mockServer.Close()
+
+			err := newTestClient(mockServer.URL).Status(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSearchHotels_SupplierQuota(t *testing.T) {
+	calls := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(dto.HotelbedsResponse{})
+	}))
+	defer mockServer.Close()
+
+	cfg := config.HotelBedsConfig{
+		BaseURL:    mockServer.URL,
+		APIKey:     "test-key",
+		APISecret:  "test-secret",
+		Timeout:    config.Duration{Duration: time.Second},
+		DailyQuota: 2,
+	}
+	limiter := NewSupplierLimiter(cfg)
+	client := NewHotelBedsClient(cfg, nil, limiter)
+	contentClient := NewHotelBedsContentClient(cfg, config.Default().Content, nil, limiter)
+
+	_, err := client.SearchHotels(context.Background(), []byte("{}"))
+	assert.NoError(t, err)
+
+	// Content calls spend the same quota as searches
+	_, err = contentClient.Hotels(context.Background(), nil, 1, 100)
+	assert.NoError(t, err)
+
+	_, err = client.SearchHotels(context.Background(), []byte("{}"))
+	assert.ErrorIs(t, err, ErrSupplierQuotaExceeded)
+	_, err = contentClient.Hotels(context.Background(), nil, 1, 100)
+	assert.ErrorIs(t, err, ErrSupplierQuotaExceeded)
+	assert.Equal(t, 2, calls)
+}
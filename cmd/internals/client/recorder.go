@@ -0,0 +1,239 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+)
+
+const (
+	operationSearchHotels  = "searchHotels"
+	operationContentHotels = "contentHotels"
+)
+
+var ErrFixtureNotFound = errors.New("no recorded fixture matches the request")
+
+// strippedFields are removed from recorded payloads: credentials and signatures must never reach a
+// fixture, and the audit data changes on every call. Compared case-insensitively.
+var strippedFields = map[string]struct{}{
+	strings.ToLower(util.HeaderApiKey):    {},
+	strings.ToLower(util.HeaderSignature): {},
+	"apikey":                              {},
+	"apisecret":                           {},
+	"secret":                              {},
+	"signature":                           {},
+	"auditdata":                           {},
+}
+
+// Fixture represents one recorded supplier exchange
+type Fixture struct {
+	Operation string          `json:"operation"`
+	Request   json.RawMessage `json:"request"`
+	Response  json.RawMessage `json:"response"`
+}
+
+// RecordingClient decorates a HotelBedsClient to capture supplier exchanges as fixtures, or to serve
+// them back without network access
+type RecordingClient struct {
+	client   HotelBedsClient
+	fixtures fixtureStore
+}
+
+// NewRecordingClient wraps client according to the recorder mode; passthrough returns client unchanged
+// and replay never calls it
+func NewRecordingClient(client HotelBedsClient, cfg config.RecorderConfig) HotelBedsClient {
+	if cfg.Mode == "" || cfg.Mode == config.RecorderModePassthrough {
+		return client
+	}
+
+	return &RecordingClient{
+		client:   client,
+		fixtures: fixtureStore{mode: cfg.Mode, dir: cfg.Dir},
+	}
+}
+
+func (r *RecordingClient) SearchHotels(ctx context.Context, request []byte) ([]byte, error) {
+	return r.fixtures.exchange(ctx, operationSearchHotels, request, func() ([]byte, error) {
+		return r.client.SearchHotels(ctx, request)
+	})
+}
+
+// Status reports the supplier as available in replay mode
+func (r *RecordingClient) Status(ctx context.Context) error {
+	if r.fixtures.mode == config.RecorderModeReplay {
+		return nil
+	}
+
+	return r.client.Status(ctx)
+}
+
+// RecordingContentClient decorates a ContentClient like RecordingClient, so content syncs are recorded
+// and replayed along with the searches
+type RecordingContentClient struct {
+	client   ContentClient
+	fixtures fixtureStore
+}
+
+// contentRequest identifies a Content API call, which has no body, in its fixture
+type contentRequest struct {
+	Codes []int `json:"codes,omitempty"`
+	From  int   `json:"from"`
+	To    int   `json:"to"`
+}
+
+// NewRecordingContentClient wraps client according to the recorder mode, like NewRecordingClient
+func NewRecordingContentClient(client ContentClient, cfg config.RecorderConfig) ContentClient {
+	if cfg.Mode == "" || cfg.Mode == config.RecorderModePassthrough {
+		return client
+	}
+
+	return &RecordingContentClient{
+		client:   client,
+		fixtures: fixtureStore{mode: cfg.Mode, dir: cfg.Dir},
+	}
+}
+
+func (r *RecordingContentClient) Hotels(ctx context.Context, codes []int, from, to int) ([]byte, error) {
+	request, err := json.Marshal(contentRequest{Codes: codes, From: from, To: to})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.fixtures.exchange(ctx, operationContentHotels, request, func() ([]byte, error) {
+		return r.client.Hotels(ctx, codes, from, to)
+	})
+}
+
+// fixtureStore keeps the fixtures of the recording clients in dir
+type fixtureStore struct {
+	mode string
+	dir  string
+}
+
+// exchange serves the fixture of the request in replay mode; otherwise it calls the supplier and records
+// the successful exchange
+func (f fixtureStore) exchange(ctx context.Context, operation string, request []byte, call func() ([]byte, error)) ([]byte, error) {
+	normalized, err := Normalize(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to normalize request: %w", err)
+	}
+
+	path := f.fixturePath(operation, normalized)
+
+	if f.mode == config.RecorderModeReplay {
+		fixture, err := ReadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var response bytes.Buffer
+		if err := json.Compact(&response, fixture.Response); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %v: %w", path, err)
+		}
+
+		slog.DebugContext(ctx, "replaying hotelbeds fixture", slog.String("fixture", path))
+		return response.Bytes(), nil
+	}
+
+	response, err := call()
+	if err != nil {
+		return response, err
+	}
+
+	// A failed recording must not fail the call it observed
+	if err := f.record(path, operation, normalized, response); err != nil {
+		slog.WarnContext(ctx, "failed to record hotelbeds fixture", slog.String("fixture", path), slog.String("error", err.Error()))
+	} else {
+		slog.DebugContext(ctx, "recorded hotelbeds fixture", slog.String("fixture", path))
+	}
+
+	return response, nil
+}
+
+func (f fixtureStore) record(path, operation string, request, response []byte) error {
+	normalized, err := Normalize(response)
+	if err != nil {
+		return fmt.Errorf("failed to normalize response: %w", err)
+	}
+
+	content, err := json.MarshalIndent(Fixture{Operation: operation, Request: request, Response: normalized}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(f.dir, 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// fixturePath names the fixture after the operation and a hash of the normalized request,
+// so equivalent requests share a fixture
+func (f fixtureStore) fixturePath(operation string, normalized []byte) string {
+	sum := sha256.Sum256(append([]byte(operation+"\n"), normalized...))
+	return filepath.Join(f.dir, fmt.Sprintf("%s-%s.json", operation, hex.EncodeToString(sum[:8])))
+}
+
+// ReadFixture loads a recorded exchange
+func ReadFixture(path string) (Fixture, error) {
+	var fixture Fixture
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fixture, fmt.Errorf("%w: %v", ErrFixtureNotFound, path)
+	}
+	if err != nil {
+		return fixture, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return fixture, fmt.Errorf("failed to parse fixture %v: %w", path, err)
+	}
+
+	return fixture, nil
+}
+
+// Normalize returns the JSON payload with sorted keys, no insignificant whitespace and the
+// stripped fields removed, so recordings are stable and free of secrets
+func Normalize(payload []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(strip(value))
+}
+
+func strip(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if _, ok := strippedFields[strings.ToLower(key)]; ok {
+				delete(v, key)
+				continue
+			}
+			v[key] = strip(nested)
+		}
+	case []any:
+		for i, nested := range v {
+			v[i] = strip(nested)
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+const fixturesDir = "testdata/fixtures"
+
+func TestNewRecordingClient_Passthrough(t *testing.T) {
+	inner := newTestClient("http://test.com")
+
+	assert.Same(t, inner, NewRecordingClient(inner, config.RecorderConfig{Mode: config.RecorderModePassthrough}))
+	assert.Same(t, inner, NewRecordingClient(inner, config.RecorderConfig{}))
+}
+
+func TestRecordingClient_RecordAndReplay(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"auditData":{"timestamp":"2030-01-01 10:00:00.000"},"hotels":{"total":1,"hotels":[{"code":1234,"minRate":"100.00","currency":"EUR"}]}}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	request := []byte(`{"stay":{"checkIn":"2030-06-01","checkOut":"2030-06-02"},"hotels":{"hotel":[1234]}}`)
+
+	recorder := NewRecordingClient(newTestClient(server.URL), config.RecorderConfig{Mode: config.RecorderModeRecord, Dir: dir})
+	recorded, err := recorder.SearchHotels(context.Background(), request)
+	assert.NoError(t, err)
+	assert.Contains(t, string(recorded), "auditData", "the caller gets the supplier response untouched")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "searchHotels-*.json"))
+	assert.Len(t, files, 1)
+
+	content, _ := os.ReadFile(files[0])
+	assert.NotContains(t, string(content), "auditData")
+	assert.NotContains(t, string(content), "test-key")
+
+	// Equivalent requests with different key order and whitespace share the fixture
+	replayer := NewRecordingClient(nil, config.RecorderConfig{Mode: config.RecorderModeReplay, Dir: dir})
+	replayed, err := replayer.SearchHotels(context.Background(), []byte(`{ "hotels": {"hotel": [1234]}, "stay": {"checkOut": "2030-06-02", "checkIn": "2030-06-01"} }`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"hotels":{"total":1,"hotels":[{"code":1234,"minRate":"100.00","currency":"EUR"}]}}`, string(replayed))
+	assert.NoError(t, replayer.Status(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	_, err = replayer.SearchHotels(context.Background(), []byte(`{"hotels":{"hotel":[5678]}}`))
+	assert.True(t, errors.Is(err, ErrFixtureNotFound))
+}
+
+func TestRecordingClient_DoesNotRecordFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := NewRecordingClient(newTestClient(server.URL), config.RecorderConfig{Mode: config.RecorderModeRecord, Dir: dir})
+
+	_, err := recorder.SearchHotels(context.Background(), []byte(`{}`))
+	assert.Error(t, err)
+
+	files, _ := os.ReadDir(dir)
+	assert.Empty(t, files)
+}
+
+// contentClientFunc adapts a function to the ContentClient interface
+type contentClientFunc func(ctx context.Context, codes []int, from, to int) ([]byte, error)
+
+func (f contentClientFunc) Hotels(ctx context.Context, codes []int, from, to int) ([]byte, error) {
+	return f(ctx, codes, from, to)
+}
+
+func TestRecordingContentClient_RecordAndReplay(t *testing.T) {
+	calls := 0
+	inner := contentClientFunc(func(ctx context.Context, codes []int, from, to int) ([]byte, error) {
+		calls++
+		return []byte(`{"auditData":{"processTime":"12"},"hotels":[{"code":1234,"name":{"content":"Zafiro Palace"}}]}`), nil
+	})
+
+	_, passthrough := NewRecordingContentClient(inner, config.RecorderConfig{}).(contentClientFunc)
+	assert.True(t, passthrough)
+
+	dir := t.TempDir()
+	recorder := NewRecordingContentClient(inner, config.RecorderConfig{Mode: config.RecorderModeRecord, Dir: dir})
+	_, err := recorder.Hotels(context.Background(), nil, 1, 100)
+	assert.NoError(t, err)
+
+	files, _ := filepath.Glob(filepath.Join(dir, "contentHotels-*.json"))
+	assert.Len(t, files, 1)
+
+	replayer := NewRecordingContentClient(nil, config.RecorderConfig{Mode: config.RecorderModeReplay, Dir: dir})
+	replayed, err := replayer.Hotels(context.Background(), nil, 1, 100)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"hotels":[{"code":1234,"name":{"content":"Zafiro Palace"}}]}`, string(replayed))
+	assert.Equal(t, 1, calls)
+
+	_, err = replayer.Hotels(context.Background(), nil, 101, 200)
+	assert.True(t, errors.Is(err, ErrFixtureNotFound), "an unrecorded page is not fetched from Hotelbeds")
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "Sorts keys and removes whitespace",
+			payload:  `{ "b": 1, "a": [ 2, 1 ] }`,
+			expected: `{"a":[2,1],"b":1}`,
+		},
+		{
+			name:     "Keeps number precision",
+			payload:  `{"net":"100.10","code":12345678901234567890}`,
+			expected: `{"code":12345678901234567890,"net":"100.10"}`,
+		},
+		{
+			name:     "Strips signatures, credentials and audit data at any depth",
+			payload:  `{"auditData":{},"X-Signature":"abc","nested":[{"Api-key":"k","apiSecret":"s","keep":true}]}`,
+			expected: `{"nested":[{"keep":true}]}`,
+		},
+		{
+			name:    "Invalid JSON",
+			payload: `{"hotels":`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			normalized, err := Normalize([]byte(tt.payload))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(normalized))
+		})
+	}
+}
+
+// TestRecordingClient_GoldenFixtures replays every captured exchange and checks it still decodes
+func TestRecordingClient_GoldenFixtures(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(fixturesDir, "*.json"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, files)
+
+	replayer := NewRecordingClient(nil, config.RecorderConfig{Mode: config.RecorderModeReplay, Dir: fixturesDir})
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			fixture, err := ReadFixture(file)
+			assert.NoError(t, err)
+
+			response, err := replayer.SearchHotels(context.Background(), fixture.Request)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(fixture.Response), string(response))
+
+			var decoded dto.HotelbedsResponse
+			assert.NoError(t, json.Unmarshal(response, &decoded))
+			assert.Equal(t, decoded.Hotels.Total, len(decoded.Hotels.Hotels))
+
+			for _, hotel := range decoded.Hotels.Hotels {
+				price, err := hotel.GetPrice()
+				assert.NoError(t, err)
+				assert.Greater(t, price, 0.0)
+			}
+		})
+	}
+}
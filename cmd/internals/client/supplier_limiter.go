@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/ratelimit"
+)
+
+var ErrSupplierQuotaExceeded = errors.New("hotelbeds daily quota exceeded")
+
+// SupplierLimiter enforces the supplier's requests-per-second and daily caps across all tenants
+type SupplierLimiter struct {
+	mu         sync.Mutex
+	bucket     *ratelimit.TokenBucket
+	dailyQuota int
+	used       int
+	day        time.Time
+	now        func() time.Time
+}
+
+// NewSupplierLimiter creates a limiter; zero limits are not enforced
+func NewSupplierLimiter(cfg config.HotelBedsConfig) *SupplierLimiter {
+	limiter := &SupplierLimiter{
+		dailyQuota: cfg.DailyQuota,
+		now:        time.Now,
+	}
+
+	if cfg.RequestsPerSecond > 0 {
+		burst := int(math.Max(1, math.Ceil(cfg.RequestsPerSecond)))
+		limiter.bucket = ratelimit.NewTokenBucket(cfg.RequestsPerSecond, burst, limiter.now())
+	}
+
+	return limiter
+}
+
+// Wait blocks until a request may be sent, or fails if the daily quota is spent or ctx is done. The daily
+// slot is reserved first so a spent quota fails fast, and refunded if ctx is done before the request is sent.
+func (l *SupplierLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	day, err := l.reserveDaily()
+	if err != nil {
+		return err
+	}
+
+	if l.bucket == nil {
+		return nil
+	}
+
+	for {
+		decision := l.bucket.Take(l.now())
+		if decision.Allowed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			l.refundDaily(day)
+			return fmt.Errorf("waiting for hotelbeds rate limit: %w", ctx.Err())
+		case <-time.After(decision.RetryAfter):
+		}
+	}
+}
+
+// Used returns the number of requests counted against today's quota
+func (l *SupplierLimiter) Used() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollover()
+	return l.used
+}
+
+// reserveDaily counts a request against today's quota and returns the day it was counted on
+func (l *SupplierLimiter) reserveDaily() (time.Time, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollover()
+	if l.dailyQuota > 0 && l.used >= l.dailyQuota {
+		return l.day, ErrSupplierQuotaExceeded
+	}
+
+	l.used++
+	return l.day, nil
+}
+
+// refundDaily gives back a reserved request that was never sent, unless the quota has reset since
+func (l *SupplierLimiter) refundDaily(day time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.rollover()
+	if l.day.Equal(day) && l.used > 0 {
+		l.used--
+	}
+}
+
+// QuotaResetsIn returns the time left until the daily quota resets at midnight UTC
+func QuotaResetsIn(now time.Time) time.Duration {
+	return now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+}
+
+// rollover resets the counter at midnight UTC
+func (l *SupplierLimiter) rollover() {
+	today := l.now().UTC().Truncate(24 * time.Hour)
+	if !today.Equal(l.day) {
+		l.day = today
+		l.used = 0
+	}
+}
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplierLimiter_DailyQuota(t *testing.T) {
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	limiter := NewSupplierLimiter(config.HotelBedsConfig{DailyQuota: 2})
+	limiter.now = func() time.Time { return now }
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.ErrorIs(t, limiter.Wait(context.Background()), ErrSupplierQuotaExceeded)
+	assert.Equal(t, 2, limiter.Used())
+
+	// The quota resets at midnight UTC
+	now = now.Add(time.Hour)
+	assert.Equal(t, 0, limiter.Used())
+	assert.NoError(t, limiter.Wait(context.Background()))
+}
+
+func TestSupplierLimiter_RequestsPerSecond(t *testing.T) {
+	limiter := NewSupplierLimiter(config.HotelBedsConfig{RequestsPerSecond: 20})
+
+	start := time.Now()
+	for i := 0; i < 21; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, limiter.Wait(ctx))
+}
+
+func TestSupplierLimiter_CancelledWaitRefundsQuota(t *testing.T) {
+	limiter := NewSupplierLimiter(config.HotelBedsConfig{RequestsPerSecond: 1, DailyQuota: 2})
+
+	assert.NoError(t, limiter.Wait(context.Background()))
+	assert.Equal(t, 1, limiter.Used())
+
+	// The bucket is empty, so the wait times out before the request is sent
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx), context.DeadlineExceeded)
+	assert.Equal(t, 1, limiter.Used())
+}
+
+func TestSupplierLimiter_Unlimited(t *testing.T) {
+	limiter := NewSupplierLimiter(config.HotelBedsConfig{})
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	var nilLimiter *SupplierLimiter
+	assert.NoError(t, nilLimiter.Wait(context.Background()))
+}
+
+func TestQuotaResetsIn(t *testing.T) {
+	assert.Equal(t, time.Hour, QuotaResetsIn(time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, 24*time.Hour, QuotaResetsIn(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
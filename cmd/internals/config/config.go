@@ -0,0 +1,634 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/logging"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+)
+
+// Config represents the complete application configuration
+type Config struct {
+	Server         ServerConfig         `yaml:"server" toml:"server"`
+	HotelBeds      HotelBedsConfig      `yaml:"hotelbeds" toml:"hotelbeds"`
+	Recorder       RecorderConfig       `yaml:"recorder" toml:"recorder"`
+	Search         SearchConfig         `yaml:"search" toml:"search"`
+	Content        ContentConfig        `yaml:"content" toml:"content"`
+	IDMapping      IDMappingConfig      `yaml:"idMapping" toml:"idMapping"`
+	Currency       CurrencyConfig       `yaml:"currency" toml:"currency"`
+	CircuitBreaker CircuitBreakerConfig `yaml:"circuitBreaker" toml:"circuitBreaker"`
+	Health         HealthConfig         `yaml:"health" toml:"health"`
+	Auth           AuthConfig           `yaml:"auth" toml:"auth"`
+	RateLimit      RateLimitConfig      `yaml:"rateLimit" toml:"rateLimit"`
+	Logging        LoggingConfig        `yaml:"logging" toml:"logging"`
+	Tracing        TracingConfig        `yaml:"tracing" toml:"tracing"`
+}
+
+// ServerConfig represents the HTTP server settings
+type ServerConfig struct {
+	Port              string   `yaml:"port" toml:"port"`
+	ReadTimeout       Duration `yaml:"readTimeout" toml:"readTimeout"`
+	ReadHeaderTimeout Duration `yaml:"readHeaderTimeout" toml:"readHeaderTimeout"`
+	WriteTimeout      Duration `yaml:"writeTimeout" toml:"writeTimeout"`
+	IdleTimeout       Duration `yaml:"idleTimeout" toml:"idleTimeout"`
+	MaxHeaderBytes    int      `yaml:"maxHeaderBytes" toml:"maxHeaderBytes"`
+	ShutdownTimeout   Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout"`
+	// ShutdownHookTimeout bounds each shutdown hook separately, so a slow drain cannot starve them
+	ShutdownHookTimeout Duration `yaml:"shutdownHookTimeout" toml:"shutdownHookTimeout"`
+}
+
+// HotelBedsConfig represents the Hotelbeds supplier endpoint and credentials
+type HotelBedsConfig struct {
+	BaseURL   string   `yaml:"baseUrl" toml:"baseUrl"`
+	APIKey    string   `yaml:"apiKey" toml:"apiKey"`
+	APISecret string   `yaml:"apiSecret" toml:"apiSecret"`
+	Timeout   Duration `yaml:"timeout" toml:"timeout"`
+	// Currency is the currency of the Hotelbeds account, which rates are quoted and price filters sent in
+	Currency string `yaml:"currency" toml:"currency"`
+	// RequestsPerSecond and DailyQuota cap outbound calls across all tenants; zero means unlimited
+	RequestsPerSecond float64 `yaml:"requestsPerSecond" toml:"requestsPerSecond"`
+	DailyQuota        int     `yaml:"dailyQuota" toml:"dailyQuota"`
+}
+
+// Recorder modes for the Hotelbeds client
+const (
+	RecorderModePassthrough = "passthrough"
+	RecorderModeRecord      = "record"
+	RecorderModeReplay      = "replay"
+)
+
+// RecorderConfig represents the record/replay settings of the Hotelbeds client
+type RecorderConfig struct {
+	Mode string `yaml:"mode" toml:"mode"`
+	Dir  string `yaml:"dir" toml:"dir"`
+}
+
+// SearchConfig represents the limits of flexible-date searches
+type SearchConfig struct {
+	// FlexibleConcurrency is the number of supplier searches a flexible search runs at once
+	FlexibleConcurrency int `yaml:"flexibleConcurrency" toml:"flexibleConcurrency"`
+	// FlexibleMaxCheckIns caps the candidate check-in dates, and so the supplier searches, per request
+	FlexibleMaxCheckIns int `yaml:"flexibleMaxCheckIns" toml:"flexibleMaxCheckIns"`
+	// CursorSecret signs the pagination cursors; when empty a random secret is generated at startup
+	CursorSecret string `yaml:"cursorSecret" toml:"cursorSecret"`
+	// CursorTTL is how long a paginated search result is kept, and so its cursors are valid
+	CursorTTL Duration `yaml:"cursorTTL" toml:"cursorTTL"`
+	// MaxPageSize caps the limit of a page; MaxPagedSearches caps the searches kept for paging
+	MaxPageSize      int `yaml:"maxPageSize" toml:"maxPageSize"`
+	MaxPagedSearches int `yaml:"maxPagedSearches" toml:"maxPagedSearches"`
+	// JobWorkers searches run at once for asynchronous search jobs, which search JobBatchSize hotels at a time
+	JobWorkers   int `yaml:"jobWorkers" toml:"jobWorkers"`
+	JobBatchSize int `yaml:"jobBatchSize" toml:"jobBatchSize"`
+	// JobQueueSize caps the batches waiting for a worker; MaxJobs caps the jobs kept until they expire
+	JobQueueSize int      `yaml:"jobQueueSize" toml:"jobQueueSize"`
+	MaxJobs      int      `yaml:"maxJobs" toml:"maxJobs"`
+	JobTTL       Duration `yaml:"jobTTL" toml:"jobTTL"`
+}
+
+// ContentConfig represents the hotel static content store and its sync with the Hotelbeds Content API
+type ContentConfig struct {
+	// SyncEnabled runs a full content sync at startup and then every SyncInterval
+	SyncEnabled  bool     `yaml:"syncEnabled" toml:"syncEnabled"`
+	SyncInterval Duration `yaml:"syncInterval" toml:"syncInterval"`
+	// PageSize is the number of hotels fetched per Content API call during a sync
+	PageSize int `yaml:"pageSize" toml:"pageSize"`
+	// StoreFile persists the synced content so a restart does not start empty; empty keeps it in memory only
+	StoreFile string `yaml:"storeFile" toml:"storeFile"`
+	Language  string `yaml:"language" toml:"language"`
+	// ImageBaseURL is prefixed to the relative image paths returned by the Content API
+	ImageBaseURL string `yaml:"imageBaseUrl" toml:"imageBaseUrl"`
+}
+
+// IDMappingConfig represents the file mapping liteAPI hotel IDs to supplier codes; without a file the
+// supplier codes are used as liteAPI IDs
+type IDMappingConfig struct {
+	File string `yaml:"file" toml:"file"`
+	// ReloadInterval is how often the file is checked for changes
+	ReloadInterval Duration `yaml:"reloadInterval" toml:"reloadInterval"`
+}
+
+// Rounding modes of converted amounts
+const (
+	RoundingHalfEven = "half-even"
+	RoundingUp       = "up"
+	RoundingDown     = "down"
+)
+
+// CurrencyConfig represents the FX policy applied when supplier prices are converted
+type CurrencyConfig struct {
+	// SpreadBps is added to every conversion rate, in basis points; Spreads overrides it per "FROM/TO" pair
+	SpreadBps int            `yaml:"spreadBps" toml:"spreadBps"`
+	Spreads   map[string]int `yaml:"spreads" toml:"spreads"`
+	// Rounding rounds converted amounts to the minor units of their currency: half-even, up or down
+	Rounding string `yaml:"rounding" toml:"rounding"`
+	// ChargeInSupplierCurrency charges guests in the supplier currency; the converted price is only displayed
+	ChargeInSupplierCurrency bool `yaml:"chargeInSupplierCurrency" toml:"chargeInSupplierCurrency"`
+	// HistoryDir holds CSV files of historical rates, loaded at startup to convert at past dates
+	HistoryDir string `yaml:"historyDir" toml:"historyDir"`
+	// HistoryReloadInterval is how often the history files are loaded again
+	HistoryReloadInterval Duration `yaml:"historyReloadInterval" toml:"historyReloadInterval"`
+}
+
+// CircuitBreakerConfig represents when the supplier circuit opens and how long it stays open
+type CircuitBreakerConfig struct {
+	FailureThreshold int      `yaml:"failureThreshold" toml:"failureThreshold"`
+	OpenTimeout      Duration `yaml:"openTimeout" toml:"openTimeout"`
+}
+
+// HealthConfig represents the readiness check settings
+type HealthConfig struct {
+	FXMaxAge         Duration `yaml:"fxMaxAge" toml:"fxMaxAge"`
+	DeepCheckTTL     Duration `yaml:"deepCheckTtl" toml:"deepCheckTtl"`
+	DeepCheckTimeout Duration `yaml:"deepCheckTimeout" toml:"deepCheckTimeout"`
+}
+
+// AuthConfig represents the API key authentication settings
+type AuthConfig struct {
+	Enabled  bool   `yaml:"enabled" toml:"enabled"`
+	KeysFile string `yaml:"keysFile" toml:"keysFile"`
+}
+
+// RateLimitConfig represents the inbound token bucket limits; tenants may override the per-tenant limit
+type RateLimitConfig struct {
+	Enabled     bool    `yaml:"enabled" toml:"enabled"`
+	TenantRPS   float64 `yaml:"tenantRps" toml:"tenantRps"`
+	TenantBurst int     `yaml:"tenantBurst" toml:"tenantBurst"`
+	GlobalRPS   float64 `yaml:"globalRps" toml:"globalRps"`
+	GlobalBurst int     `yaml:"globalBurst" toml:"globalBurst"`
+}
+
+// LoggingConfig represents the logger settings
+type LoggingConfig struct {
+	Level string `yaml:"level" toml:"level"`
+}
+
+// TracingConfig represents the tracing exporter settings
+type TracingConfig struct {
+	Exporter    string `yaml:"exporter" toml:"exporter"`
+	ServiceName string `yaml:"serviceName" toml:"serviceName"`
+}
+
+// Duration wraps time.Duration so it can be written as "10s" in YAML and TOML files
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Default returns the configuration used when nothing overrides a setting
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:                "8080",
+			ReadTimeout:         Duration{15 * time.Second},
+			ReadHeaderTimeout:   Duration{5 * time.Second},
+			WriteTimeout:        Duration{30 * time.Second},
+			IdleTimeout:         Duration{60 * time.Second},
+			MaxHeaderBytes:      1 << 20,
+			ShutdownTimeout:     Duration{20 * time.Second},
+			ShutdownHookTimeout: Duration{10 * time.Second},
+		},
+		HotelBeds: HotelBedsConfig{
+			BaseURL:  "https://api.test.hotelbeds.com",
+			Timeout:  Duration{10 * time.Second},
+			Currency: "EUR",
+		},
+		Recorder: RecorderConfig{
+			Mode: RecorderModePassthrough,
+		},
+		Search: SearchConfig{
+			FlexibleConcurrency: 4,
+			FlexibleMaxCheckIns: 31,
+			CursorTTL:           Duration{15 * time.Minute},
+			MaxPageSize:         100,
+			MaxPagedSearches:    1000,
+			JobWorkers:          4,
+			JobBatchSize:        50,
+			JobQueueSize:        1000,
+			MaxJobs:             1000,
+			JobTTL:              Duration{time.Hour},
+		},
+		Content: ContentConfig{
+			SyncEnabled:  false,
+			SyncInterval: Duration{24 * time.Hour},
+			PageSize:     1000,
+			Language:     "ENG",
+			ImageBaseURL: "https://photos.hotelbeds.com/giata/",
+		},
+		IDMapping: IDMappingConfig{
+			ReloadInterval: Duration{30 * time.Second},
+		},
+		Currency: CurrencyConfig{
+			Rounding:              RoundingHalfEven,
+			HistoryReloadInterval: Duration{time.Hour},
+		},
+		CircuitBreaker: CircuitBreakerConfig{
+			FailureThreshold: 5,
+			OpenTimeout:      Duration{30 * time.Second},
+		},
+		Health: HealthConfig{
+			FXMaxAge:         Duration{24 * time.Hour},
+			DeepCheckTTL:     Duration{30 * time.Second},
+			DeepCheckTimeout: Duration{5 * time.Second},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     false,
+			TenantRPS:   5,
+			TenantBurst: 10,
+			GlobalRPS:   50,
+			GlobalBurst: 100,
+		},
+		Logging: LoggingConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    telemetry.ExporterNone,
+			ServiceName: "nuitee-mohit-jain",
+		},
+	}
+}
+
+// Load builds the configuration from defaults, an optional YAML/TOML file, the environment
+// and command line flags, in increasing order of precedence, and validates the result.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("nuitee-mohit-jain", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	port := flags.String("port", "", "HTTP port to listen on")
+	logLevel := flags.String("log-level", "", "log level: debug, info, warn or error")
+	baseURL := flags.String("hotelbeds-base-url", "", "Hotelbeds API base URL")
+	if err := flags.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(*configFile, &cfg); err != nil {
+			return cfg, err
+		}
+	}
+
+	if err := loadEnv(&cfg); err != nil {
+		return cfg, err
+	}
+
+	// Flags take precedence over everything else
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "log-level":
+			cfg.Logging.Level = *logLevel
+		case "hotelbeds-base-url":
+			cfg.HotelBeds.BaseURL = *baseURL
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile decodes the config file on top of cfg, choosing the format from the file extension
+func loadFile(path string, cfg *Config) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, cfg)
+	case ".toml":
+		err = toml.Unmarshal(content, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format: %v", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %v: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv overrides cfg with any environment variables that are set.
+// HOTELBEDS_API_KEY and HOTELBEDS_API_SECRET are accepted as aliases of the canonical names.
+func loadEnv(cfg *Config) error {
+	setString(&cfg.Server.Port, "PORT")
+	setString(&cfg.HotelBeds.BaseURL, "HOTEL_BEDS_BASE_URL")
+	setString(&cfg.HotelBeds.Currency, "HOTEL_BEDS_CURRENCY")
+	setString(&cfg.HotelBeds.APIKey, "HOTELBEDS_API_KEY", "HOTEL_BEDS_API_KEY")
+	setString(&cfg.HotelBeds.APISecret, "HOTELBEDS_API_SECRET", "HOTEL_BEDS_SECRET")
+	setString(&cfg.Recorder.Mode, "HOTEL_BEDS_RECORDER_MODE")
+	setString(&cfg.Recorder.Dir, "HOTEL_BEDS_FIXTURES_DIR")
+	setString(&cfg.Search.CursorSecret, "SEARCH_CURSOR_SECRET")
+	setString(&cfg.Content.StoreFile, "CONTENT_STORE_FILE")
+	setString(&cfg.Content.Language, "CONTENT_LANGUAGE")
+	setString(&cfg.Content.ImageBaseURL, "CONTENT_IMAGE_BASE_URL")
+	setString(&cfg.IDMapping.File, "ID_MAPPING_FILE")
+	setString(&cfg.Currency.Rounding, "CURRENCY_ROUNDING")
+	setString(&cfg.Currency.HistoryDir, "CURRENCY_HISTORY_DIR")
+	setString(&cfg.Auth.KeysFile, "AUTH_KEYS_FILE")
+	setString(&cfg.Logging.Level, "LOG_LEVEL")
+	setString(&cfg.Tracing.Exporter, "OTEL_TRACES_EXPORTER")
+	setString(&cfg.Tracing.ServiceName, "OTEL_SERVICE_NAME")
+
+	return errors.Join(
+		setDuration(&cfg.Server.ReadTimeout, "SERVER_READ_TIMEOUT"),
+		setDuration(&cfg.Server.ReadHeaderTimeout, "SERVER_READ_HEADER_TIMEOUT"),
+		setDuration(&cfg.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT"),
+		setDuration(&cfg.Server.IdleTimeout, "SERVER_IDLE_TIMEOUT"),
+		setInt(&cfg.Server.MaxHeaderBytes, "SERVER_MAX_HEADER_BYTES"),
+		setDuration(&cfg.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT"),
+		setDuration(&cfg.Server.ShutdownHookTimeout, "SERVER_SHUTDOWN_HOOK_TIMEOUT"),
+		setDuration(&cfg.HotelBeds.Timeout, "HOTEL_BEDS_TIMEOUT"),
+		setInt(&cfg.Search.FlexibleConcurrency, "SEARCH_FLEXIBLE_CONCURRENCY"),
+		setInt(&cfg.Search.FlexibleMaxCheckIns, "SEARCH_FLEXIBLE_MAX_CHECK_INS"),
+		setDuration(&cfg.Search.CursorTTL, "SEARCH_CURSOR_TTL"),
+		setInt(&cfg.Search.MaxPageSize, "SEARCH_MAX_PAGE_SIZE"),
+		setInt(&cfg.Search.MaxPagedSearches, "SEARCH_MAX_PAGED_SEARCHES"),
+		setInt(&cfg.Search.JobWorkers, "SEARCH_JOB_WORKERS"),
+		setInt(&cfg.Search.JobBatchSize, "SEARCH_JOB_BATCH_SIZE"),
+		setInt(&cfg.Search.JobQueueSize, "SEARCH_JOB_QUEUE_SIZE"),
+		setInt(&cfg.Search.MaxJobs, "SEARCH_MAX_JOBS"),
+		setDuration(&cfg.Search.JobTTL, "SEARCH_JOB_TTL"),
+		setBool(&cfg.Content.SyncEnabled, "CONTENT_SYNC_ENABLED"),
+		setDuration(&cfg.Content.SyncInterval, "CONTENT_SYNC_INTERVAL"),
+		setInt(&cfg.Content.PageSize, "CONTENT_PAGE_SIZE"),
+		setDuration(&cfg.IDMapping.ReloadInterval, "ID_MAPPING_RELOAD_INTERVAL"),
+		setInt(&cfg.Currency.SpreadBps, "CURRENCY_SPREAD_BPS"),
+		setBool(&cfg.Currency.ChargeInSupplierCurrency, "CURRENCY_CHARGE_IN_SUPPLIER_CURRENCY"),
+		setDuration(&cfg.Currency.HistoryReloadInterval, "CURRENCY_HISTORY_RELOAD_INTERVAL"),
+		setInt(&cfg.CircuitBreaker.FailureThreshold, "CIRCUIT_BREAKER_FAILURE_THRESHOLD"),
+		setDuration(&cfg.CircuitBreaker.OpenTimeout, "CIRCUIT_BREAKER_OPEN_TIMEOUT"),
+		setDuration(&cfg.Health.FXMaxAge, "HEALTH_FX_MAX_AGE"),
+		setDuration(&cfg.Health.DeepCheckTTL, "HEALTH_DEEP_CHECK_TTL"),
+		setDuration(&cfg.Health.DeepCheckTimeout, "HEALTH_DEEP_CHECK_TIMEOUT"),
+		setBool(&cfg.Auth.Enabled, "AUTH_ENABLED"),
+		setFloat(&cfg.HotelBeds.RequestsPerSecond, "HOTEL_BEDS_REQUESTS_PER_SECOND"),
+		setInt(&cfg.HotelBeds.DailyQuota, "HOTEL_BEDS_DAILY_QUOTA"),
+		setBool(&cfg.RateLimit.Enabled, "RATE_LIMIT_ENABLED"),
+		setFloat(&cfg.RateLimit.TenantRPS, "RATE_LIMIT_TENANT_RPS"),
+		setInt(&cfg.RateLimit.TenantBurst, "RATE_LIMIT_TENANT_BURST"),
+		setFloat(&cfg.RateLimit.GlobalRPS, "RATE_LIMIT_GLOBAL_RPS"),
+		setInt(&cfg.RateLimit.GlobalBurst, "RATE_LIMIT_GLOBAL_BURST"),
+	)
+}
+
+// setString assigns the value of the last set variable among names, so later names win
+func setString(target *string, names ...string) {
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok && v != "" {
+			*target = v
+		}
+	}
+}
+
+func setDuration(target *Duration, name string) error {
+	v, ok := os.LookupEnv(name)
+	if !ok || v == "" {
+		return nil
+	}
+
+	if err := target.UnmarshalText([]byte(v)); err != nil {
+		return fmt.Errorf("invalid %v: %w", name, err)
+	}
+
+	return nil
+}
+
+func setInt(target *int, name string) error {
+	v, ok := os.LookupEnv(name)
+	if !ok || v == "" {
+		return nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("invalid %v: %w", name, err)
+	}
+
+	*target = i
+	return nil
+}
+
+func setFloat(target *float64, name string) error {
+	v, ok := os.LookupEnv(name)
+	if !ok || v == "" {
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %v: %w", name, err)
+	}
+
+	*target = f
+	return nil
+}
+
+func setBool(target *bool, name string) error {
+	v, ok := os.LookupEnv(name)
+	if !ok || v == "" {
+		return nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("invalid %v: %w", name, err)
+	}
+
+	*target = b
+	return nil
+}
+
+// Validate reports every invalid or missing setting at once
+func (c Config) Validate() error {
+	var errs []error
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port must be a number between 1 and 65535, got %q", c.Server.Port))
+	}
+
+	for _, timeout := range []struct {
+		name  string
+		value Duration
+	}{
+		{"server.readTimeout", c.Server.ReadTimeout},
+		{"server.readHeaderTimeout", c.Server.ReadHeaderTimeout},
+		{"server.writeTimeout", c.Server.WriteTimeout},
+		{"server.idleTimeout", c.Server.IdleTimeout},
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+		{"server.shutdownHookTimeout", c.Server.ShutdownHookTimeout},
+	} {
+		if timeout.value.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("%v must be positive", timeout.name))
+		}
+	}
+
+	if c.Server.MaxHeaderBytes <= 0 {
+		errs = append(errs, errors.New("server.maxHeaderBytes must be positive"))
+	}
+
+	if u, err := url.Parse(c.HotelBeds.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("hotelbeds.baseUrl must be an absolute http(s) URL, got %q", c.HotelBeds.BaseURL))
+	}
+
+	// Replaying fixtures never reaches the supplier, so it needs no credentials
+	if c.Recorder.Mode != RecorderModeReplay {
+		if c.HotelBeds.APIKey == "" {
+			errs = append(errs, errors.New("hotelbeds.apiKey is required"))
+		}
+
+		if c.HotelBeds.APISecret == "" {
+			errs = append(errs, errors.New("hotelbeds.apiSecret is required"))
+		}
+	}
+
+	switch c.Recorder.Mode {
+	case RecorderModePassthrough:
+	case RecorderModeRecord, RecorderModeReplay:
+		if c.Recorder.Dir == "" {
+			errs = append(errs, fmt.Errorf("recorder.dir is required in %v mode", c.Recorder.Mode))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("recorder.mode must be one of passthrough, record or replay, got %q", c.Recorder.Mode))
+	}
+
+	if c.HotelBeds.Timeout.Duration <= 0 {
+		errs = append(errs, errors.New("hotelbeds.timeout must be positive"))
+	}
+
+	if len(c.HotelBeds.Currency) != 3 || strings.ToUpper(c.HotelBeds.Currency) != c.HotelBeds.Currency {
+		errs = append(errs, fmt.Errorf("hotelbeds.currency must be an ISO 4217 code, got %q", c.HotelBeds.Currency))
+	}
+
+	if c.Search.FlexibleConcurrency < 1 || c.Search.FlexibleMaxCheckIns < 1 {
+		errs = append(errs, errors.New("search.flexibleConcurrency and search.flexibleMaxCheckIns must be at least 1"))
+	}
+
+	if c.Search.CursorTTL.Duration <= 0 {
+		errs = append(errs, errors.New("search.cursorTTL must be positive"))
+	}
+
+	if c.Search.MaxPageSize < 1 || c.Search.MaxPagedSearches < 1 {
+		errs = append(errs, errors.New("search.maxPageSize and search.maxPagedSearches must be at least 1"))
+	}
+
+	if c.Search.JobWorkers < 1 || c.Search.JobBatchSize < 1 || c.Search.JobQueueSize < 1 || c.Search.MaxJobs < 1 {
+		errs = append(errs, errors.New("search.jobWorkers, search.jobBatchSize, search.jobQueueSize and search.maxJobs must be at least 1"))
+	}
+
+	if c.Search.JobTTL.Duration <= 0 {
+		errs = append(errs, errors.New("search.jobTTL must be positive"))
+	}
+
+	if c.Content.SyncEnabled && c.Content.SyncInterval.Duration <= 0 {
+		errs = append(errs, errors.New("content.syncInterval must be positive when content sync is enabled"))
+	}
+
+	if c.Content.PageSize < 1 || c.Content.PageSize > 1000 {
+		errs = append(errs, fmt.Errorf("content.pageSize must be between 1 and 1000, got %d", c.Content.PageSize))
+	}
+
+	if c.Content.Language == "" {
+		errs = append(errs, errors.New("content.language is required"))
+	}
+
+	if c.IDMapping.File != "" && c.IDMapping.ReloadInterval.Duration <= 0 {
+		errs = append(errs, errors.New("idMapping.reloadInterval must be positive when an id mapping file is set"))
+	}
+
+	if c.Currency.HistoryDir != "" && c.Currency.HistoryReloadInterval.Duration <= 0 {
+		errs = append(errs, errors.New("currency.historyReloadInterval must be positive when a history directory is set"))
+	}
+
+	if c.Currency.SpreadBps < 0 || c.Currency.SpreadBps >= 10000 {
+		errs = append(errs, fmt.Errorf("currency.spreadBps must be between 0 and 9999, got %d", c.Currency.SpreadBps))
+	}
+
+	for pair, spread := range c.Currency.Spreads {
+		if len(pair) != 7 || pair[3] != '/' || strings.ToUpper(pair) != pair {
+			errs = append(errs, fmt.Errorf("currency.spreads keys must be currency pairs such as USD/EUR, got %q", pair))
+		}
+		if spread < 0 || spread >= 10000 {
+			errs = append(errs, fmt.Errorf("currency.spreads %v must be between 0 and 9999, got %d", pair, spread))
+		}
+	}
+
+	switch c.Currency.Rounding {
+	case RoundingHalfEven, RoundingUp, RoundingDown:
+	default:
+		errs = append(errs, fmt.Errorf("currency.rounding must be one of half-even, up or down, got %q", c.Currency.Rounding))
+	}
+
+	if c.CircuitBreaker.FailureThreshold < 0 {
+		errs = append(errs, errors.New("circuitBreaker.failureThreshold must not be negative"))
+	}
+
+	if c.CircuitBreaker.FailureThreshold > 0 && c.CircuitBreaker.OpenTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("circuitBreaker.openTimeout must be positive"))
+	}
+
+	if c.Health.FXMaxAge.Duration <= 0 {
+		errs = append(errs, errors.New("health.fxMaxAge must be positive"))
+	}
+
+	if c.Health.DeepCheckTTL.Duration < 0 {
+		errs = append(errs, errors.New("health.deepCheckTtl must not be negative"))
+	}
+
+	if c.Health.DeepCheckTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("health.deepCheckTimeout must be positive"))
+	}
+
+	if c.HotelBeds.RequestsPerSecond < 0 || c.HotelBeds.DailyQuota < 0 {
+		errs = append(errs, errors.New("hotelbeds.requestsPerSecond and hotelbeds.dailyQuota must not be negative"))
+	}
+
+	if c.RateLimit.Enabled && (c.RateLimit.TenantRPS <= 0 || c.RateLimit.TenantBurst < 1 || c.RateLimit.GlobalRPS <= 0 || c.RateLimit.GlobalBurst < 1) {
+		errs = append(errs, errors.New("rateLimit rates must be positive and bursts at least 1 when rate limiting is enabled"))
+	}
+
+	if c.Auth.Enabled && c.Auth.KeysFile == "" {
+		errs = append(errs, errors.New("auth.keysFile is required when auth is enabled"))
+	}
+
+	if _, err := logging.ParseLevel(c.Logging.Level); err != nil {
+		errs = append(errs, fmt.Errorf("logging.level: %w", err))
+	}
+
+	switch c.Tracing.Exporter {
+	case telemetry.ExporterOTLP, telemetry.ExporterStdout, telemetry.ExporterNone:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter must be one of otlp, stdout or none, got %q", c.Tracing.Exporter))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,215 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setCredentials(t *testing.T) {
+	t.Setenv("HOTEL_BEDS_API_KEY", "test-key")
+	t.Setenv("HOTEL_BEDS_SECRET", "test-secret")
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setCredentials(t)
+	t.Setenv("PORT", "")
+
+	cfg, err := Load(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "8080", cfg.Server.Port)
+	assert.Equal(t, 20*time.Second, cfg.Server.ShutdownTimeout.Duration)
+	assert.Equal(t, 10*time.Second, cfg.Server.ShutdownHookTimeout.Duration)
+	assert.Equal(t, "https://api.test.hotelbeds.com", cfg.HotelBeds.BaseURL)
+	assert.Equal(t, "test-key", cfg.HotelBeds.APIKey)
+	assert.Equal(t, "test-secret", cfg.HotelBeds.APISecret)
+	assert.Equal(t, 10*time.Second, cfg.HotelBeds.Timeout.Duration)
+	assert.Equal(t, 5, cfg.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, "info", cfg.Logging.Level)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	setCredentials(t)
+	path := writeFile(t, "config.yaml", `
+server:
+  port: "9000"
+hotelbeds:
+  timeout: 3s
+circuitBreaker:
+  failureThreshold: 4
+logging:
+  level: debug
+`)
+	t.Setenv("PORT", "9100")
+	t.Setenv("CIRCUIT_BREAKER_OPEN_TIMEOUT", "1s")
+
+	cfg, err := Load([]string{"-config", path, "-log-level", "warn"})
+	assert.NoError(t, err)
+
+	// file overrides defaults
+	assert.Equal(t, 3*time.Second, cfg.HotelBeds.Timeout.Duration)
+	assert.Equal(t, 4, cfg.CircuitBreaker.FailureThreshold)
+	// env overrides file
+	assert.Equal(t, "9100", cfg.Server.Port)
+	assert.Equal(t, time.Second, cfg.CircuitBreaker.OpenTimeout.Duration)
+	// flags override env and file
+	assert.Equal(t, "warn", cfg.Logging.Level)
+}
+
+func TestLoad_TOML(t *testing.T) {
+	setCredentials(t)
+	path := writeFile(t, "config.toml", `
+[server]
+port = "7000"
+
+[circuitBreaker]
+failureThreshold = 3
+openTimeout = "30s"
+
+[currency.spreads]
+"USD/EUR" = 50
+`)
+
+	cfg, err := Load([]string{"-config", path})
+	assert.NoError(t, err)
+	assert.Equal(t, "7000", cfg.Server.Port)
+	assert.Equal(t, 3, cfg.CircuitBreaker.FailureThreshold)
+	assert.Equal(t, 30*time.Second, cfg.CircuitBreaker.OpenTimeout.Duration)
+	assert.Equal(t, map[string]int{"USD/EUR": 50}, cfg.Currency.Spreads)
+}
+
+func TestLoad_CredentialAliases(t *testing.T) {
+	t.Setenv("HOTELBEDS_API_KEY", "alias-key")
+	t.Setenv("HOTELBEDS_API_SECRET", "alias-secret")
+
+	cfg, err := Load(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "alias-key", cfg.HotelBeds.APIKey)
+	assert.Equal(t, "alias-secret", cfg.HotelBeds.APISecret)
+}
+
+func TestLoad_ReplayWithoutCredentials(t *testing.T) {
+	t.Setenv("HOTEL_BEDS_RECORDER_MODE", "replay")
+	t.Setenv("HOTEL_BEDS_FIXTURES_DIR", "testdata/fixtures")
+
+	cfg, err := Load(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, RecorderModeReplay, cfg.Recorder.Mode)
+	assert.Equal(t, "testdata/fixtures", cfg.Recorder.Dir)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		args        []string
+		errContains []string
+	}{
+		{
+			name:        "Missing credentials",
+			errContains: []string{"hotelbeds.apiKey is required", "hotelbeds.apiSecret is required"},
+		},
+		{
+			name:        "Invalid port",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "PORT": "abc"},
+			errContains: []string{"server.port"},
+		},
+		{
+			name:        "Invalid base URL",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s"},
+			args:        []string{"-hotelbeds-base-url", "not a url"},
+			errContains: []string{"hotelbeds.baseUrl"},
+		},
+		{
+			name:        "Invalid supplier currency",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "HOTEL_BEDS_CURRENCY": "euro"},
+			errContains: []string{"hotelbeds.currency"},
+		},
+		{
+			name:        "Unparsable duration",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "HOTEL_BEDS_TIMEOUT": "ten"},
+			errContains: []string{"invalid HOTEL_BEDS_TIMEOUT"},
+		},
+		{
+			name:        "Invalid log level and exporter",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "LOG_LEVEL": "loud", "OTEL_TRACES_EXPORTER": "jaeger"},
+			errContains: []string{"logging.level", "tracing.exporter"},
+		},
+		{
+			name:        "Recorder without fixtures directory",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "HOTEL_BEDS_RECORDER_MODE": "record"},
+			errContains: []string{"recorder.dir is required in record mode"},
+		},
+		{
+			name:        "Unknown recorder mode",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "HOTEL_BEDS_RECORDER_MODE": "mock"},
+			errContains: []string{"recorder.mode"},
+		},
+		{
+			name:        "Invalid pagination",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "SEARCH_CURSOR_TTL": "0s", "SEARCH_MAX_PAGE_SIZE": "0"},
+			errContains: []string{"search.cursorTTL", "search.maxPageSize"},
+		},
+		{
+			name:        "Invalid content sync",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "CONTENT_SYNC_ENABLED": "true", "CONTENT_SYNC_INTERVAL": "0s", "CONTENT_PAGE_SIZE": "5000"},
+			errContains: []string{"content.syncInterval", "content.pageSize"},
+		},
+		{
+			name:        "Invalid id mapping reload",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "ID_MAPPING_FILE": "ids.csv", "ID_MAPPING_RELOAD_INTERVAL": "0s"},
+			errContains: []string{"idMapping.reloadInterval"},
+		},
+		{
+			name:        "Invalid rate history reload",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "CURRENCY_HISTORY_DIR": "rates", "CURRENCY_HISTORY_RELOAD_INTERVAL": "0s"},
+			errContains: []string{"currency.historyReloadInterval"},
+		},
+		{
+			name:        "Invalid search jobs",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "SEARCH_JOB_WORKERS": "0", "SEARCH_JOB_TTL": "0s"},
+			errContains: []string{"search.jobWorkers", "search.jobTTL"},
+		},
+		{
+			name:        "Invalid currency policy",
+			env:         map[string]string{"HOTEL_BEDS_API_KEY": "k", "HOTEL_BEDS_SECRET": "s", "CURRENCY_SPREAD_BPS": "-5", "CURRENCY_ROUNDING": "nearest"},
+			errContains: []string{"currency.spreadBps", "currency.rounding"},
+		},
+		{
+			name:        "Missing config file",
+			args:        []string{"-config", "/does/not/exist.yaml"},
+			errContains: []string{"failed to read config file"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, err := Load(tt.args)
+			assert.Error(t, err)
+			for _, contains := range tt.errContains {
+				assert.Contains(t, err.Error(), contains)
+			}
+		})
+	}
+}
+
+func TestLoad_UnsupportedFormat(t *testing.T) {
+	path := writeFile(t, "config.ini", "port=8080")
+
+	_, err := Load([]string{"-config", path})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported config file format")
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Board represents a liteAPI meal plan, independent of the supplier's board codes
+type Board string
+
+const (
+	BoardRoomOnly        Board = "ROOM_ONLY"
+	BoardBedAndBreakfast Board = "BED_AND_BREAKFAST"
+	BoardHalfBoard       Board = "HALF_BOARD"
+	BoardFullBoard       Board = "FULL_BOARD"
+	BoardAllInclusive    Board = "ALL_INCLUSIVE"
+	// BoardOther is returned for supplier boards without a liteAPI equivalent
+	BoardOther Board = "OTHER"
+)
+
+// hotelbedsBoards maps Hotelbeds board codes to liteAPI boards; variants of a meal plan share a board
+var hotelbedsBoards = map[string]Board{
+	"RO": BoardRoomOnly,
+	"SC": BoardRoomOnly,
+	"BB": BoardBedAndBreakfast,
+	"AB": BoardBedAndBreakfast,
+	"CB": BoardBedAndBreakfast,
+	"HB": BoardHalfBoard,
+	"FB": BoardFullBoard,
+	"AI": BoardAllInclusive,
+	"TI": BoardAllInclusive,
+}
+
+// BoardFromHotelbeds normalizes a Hotelbeds board code
+func BoardFromHotelbeds(code string) Board {
+	if board, ok := hotelbedsBoards[strings.ToUpper(code)]; ok {
+		return board
+	}
+
+	return BoardOther
+}
+
+// HotelbedsCodes returns the Hotelbeds board codes normalized to b
+func (b Board) HotelbedsCodes() []string {
+	var codes []string
+	for code, board := range hotelbedsBoards {
+		if board == b {
+			codes = append(codes, code)
+		}
+	}
+
+	return codes
+}
+
+// ParseBoards parses a comma separated list of liteAPI boards
+func ParseBoards(value string) ([]Board, error) {
+	var boards []Board
+	for _, name := range strings.Split(value, ",") {
+		board := Board(strings.ToUpper(strings.TrimSpace(name)))
+		switch board {
+		case BoardRoomOnly, BoardBedAndBreakfast, BoardHalfBoard, BoardFullBoard, BoardAllInclusive:
+			boards = append(boards, board)
+		default:
+			return nil, fmt.Errorf("unknown board: %v", name)
+		}
+	}
+
+	return boards, nil
+}
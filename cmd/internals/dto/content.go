@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"strconv"
+	"unicode"
+)
+
+// HotelbedsContentResponse represents a page of the Hotelbeds Content API hotels endpoint
+type HotelbedsContentResponse struct {
+	From   int            `json:"from"`
+	To     int            `json:"to"`
+	Total  int            `json:"total"`
+	Hotels []ContentHotel `json:"hotels"`
+}
+
+type ContentHotel struct {
+	Code            int                `json:"code"`
+	Name            ContentText        `json:"name"`
+	Description     ContentText        `json:"description"`
+	CountryCode     string             `json:"countryCode,omitempty"`
+	DestinationCode string             `json:"destinationCode,omitempty"`
+	CategoryCode    string             `json:"categoryCode,omitempty"`
+	Coordinates     *ContentCoordinate `json:"coordinates,omitempty"`
+	Address         ContentText        `json:"address"`
+	City            ContentText        `json:"city"`
+	Facilities      []ContentFacility  `json:"facilities,omitempty"`
+	Images          []ContentImage     `json:"images,omitempty"`
+}
+
+// ContentText represents a translated text of the Content API
+type ContentText struct {
+	Content string `json:"content"`
+}
+
+type ContentCoordinate struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type ContentFacility struct {
+	FacilityCode      int         `json:"facilityCode"`
+	FacilityGroupCode int         `json:"facilityGroupCode"`
+	Description       ContentText `json:"description"`
+}
+
+// ContentImage represents a hotel image; Path is relative to the image base URL
+type ContentImage struct {
+	ImageTypeCode string `json:"imageTypeCode"`
+	Path          string `json:"path"`
+	Order         int    `json:"order"`
+	VisualOrder   int    `json:"visualOrder"`
+}
+
+// HotelContent represents the liteAPI static content of a hotel
+type HotelContent struct {
+	HotelID         string           `json:"hotelId"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description,omitempty"`
+	Category        string           `json:"category,omitempty"`
+	Stars           int              `json:"stars,omitempty"`
+	CountryCode     string           `json:"countryCode,omitempty"`
+	DestinationCode string           `json:"destinationCode,omitempty"`
+	Address         string           `json:"address,omitempty"`
+	City            string           `json:"city,omitempty"`
+	Coordinates     *ContentLocation `json:"coordinates,omitempty"`
+	Facilities      []HotelFacility  `json:"facilities,omitempty"`
+	Images          []HotelImage     `json:"images,omitempty"`
+}
+
+type ContentLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+type HotelFacility struct {
+	Code        int    `json:"code"`
+	Group       int    `json:"group"`
+	Description string `json:"description,omitempty"`
+}
+
+// HotelImage represents an image of the hotel; images are listed in display order
+type HotelImage struct {
+	URL  string `json:"url"`
+	Type string `json:"type,omitempty"`
+}
+
+// StarsFromCategory returns the star rating of a Hotelbeds category code such as "4EST" or "3LL",
+// or zero for categories without stars such as apartments and hostels
+func StarsFromCategory(code string) int {
+	if code == "" || !unicode.IsDigit(rune(code[0])) {
+		return 0
+	}
+
+	stars, _ := strconv.Atoi(code[:1])
+
+	return stars
+}
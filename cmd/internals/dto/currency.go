@@ -0,0 +1,71 @@
+package dto
+
+import "time"
+
+// Currency represents a supported ISO 4217 currency; Decimals is the number of minor unit digits
+type Currency struct {
+	Code        string `json:"code"`
+	NumericCode string `json:"numericCode"`
+	Decimals    int    `json:"decimals"`
+	Symbol      string `json:"symbol"`
+}
+
+// CurrencyListResponse represents the supported currencies
+type CurrencyListResponse struct {
+	Data []Currency `json:"data"`
+}
+
+// CurrencyConvertQueryParams represents the query params of a conversion
+type CurrencyConvertQueryParams struct {
+	Amount *float64 `form:"amount" binding:"required"`
+	From   string   `form:"from" binding:"required"`
+	To     string   `form:"to" binding:"required"`
+	// At converts at the historical rate valid on that date, YYYY-MM-DD
+	At string `form:"at"`
+}
+
+// ConversionResponse represents a converted amount, rounded to the decimals of the target currency,
+// and the rate it was converted at
+type ConversionResponse struct {
+	Amount    float64 `json:"amount"`
+	From      string  `json:"from"`
+	To        string  `json:"to"`
+	Converted float64 `json:"converted"`
+	Rate      float64 `json:"rate"`
+	RatesAsOf string  `json:"ratesAsOf"`
+}
+
+// RateTableResponse represents the rate of one unit of Base in each supported currency
+type RateTableResponse struct {
+	Base      string             `json:"base"`
+	RatesAsOf string             `json:"ratesAsOf"`
+	Rates     map[string]float64 `json:"rates"`
+}
+
+// ConversionOptions represents the FX policy of a conversion; the zero value converts at the plain rate
+// without rounding
+type ConversionOptions struct {
+	// SpreadBps is added to the rate, in basis points
+	SpreadBps int
+	// Rounding rounds the converted amount to the minor units of the target currency: half-even, up or down.
+	// Empty leaves the amount unrounded.
+	Rounding string
+	// DisplayOnly charges the guest in the source currency; the converted amount is only shown
+	DisplayOnly bool
+}
+
+// Conversion represents a converted amount and the rate it was converted at, spread included
+type Conversion struct {
+	Amount float64
+	Rate   float64
+	// AsOf is the day of the rate, for conversions at a past date
+	AsOf time.Time
+}
+
+// RateGapReport represents the days of a range without any exchange rate
+type RateGapReport struct {
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Days        int      `json:"days"`
+	MissingDays []string `json:"missingDays"`
+}
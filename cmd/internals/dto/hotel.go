@@ -6,17 +6,62 @@ type HotelSearchQueryParams struct {
 	CheckOut         string `form:"checkout" binding:"required"`
 	Currency         string `form:"currency"`
 	GuestNationality string `form:"guestNationality"`
-	HotelIds         string `form:"hotelIds" binding:"required"`
 	Occupancies      string `form:"occupancies" binding:"required"`
+	// Exactly one of HotelIds, Destination or Latitude/Longitude selects the hotels searched
+	HotelIds    string   `form:"hotelIds"`
+	Destination string   `form:"destination"`
+	Latitude    *float64 `form:"latitude"`
+	Longitude   *float64 `form:"longitude"`
+	// Radius around Latitude/Longitude in Unit, km or mi
+	Radius float64 `form:"radius"`
+	Unit   string  `form:"unit"`
+	// Refundable drops non-refundable rates before the price is selected
+	Refundable bool `form:"refundable"`
+	// Boards is a comma separated list of liteAPI boards the rates must have
+	Boards string `form:"boards"`
+	// MinPrice and MaxPrice bound the stay price in the requested currency
+	MinPrice    float64 `form:"minPrice"`
+	MaxPrice    float64 `form:"maxPrice"`
+	PaymentType string  `form:"paymentType"`
+	Packaging   *bool   `form:"packaging"`
+	RateClass   string  `form:"rateClass"`
+	// Sort orders the hotels and Cursor requests the next page of a paged search; the page size, limit,
+	// is read before binding so an invalid one is reported as an invalid page
+	Sort   string `form:"sort"`
+	Cursor string `form:"cursor"`
+	// Flexible turns checkin/checkout into a window in which a stay of Nights nights is searched
+	Flexible bool `form:"flexible"`
+	Nights   int  `form:"nights"`
 }
 
 // HotelSearchServiceParams represents the request structure for HotelSearch Service
 type HotelSearchServiceParams struct {
 	CheckIn     string
 	CheckOut    string
-	HotelIDs    []int
+	HotelIDs    []string
+	Destination string
+	Geolocation *Geolocation
 	Currency    string
 	Occupancies []Occupancy
+	Filters     RateFilters
+	Sort        SortOrder
+}
+
+// RateFilters represents the conditions a rate must meet to be priced; zero values do not filter
+type RateFilters struct {
+	Refundable bool
+	Boards     []Board
+	// MinPrice and MaxPrice bound the selling price of the stay, in the requested currency
+	MinPrice    float64
+	MaxPrice    float64
+	PaymentType string
+	Packaging   *bool
+	RateClass   string
+}
+
+// Active reports whether any filter is set
+func (f RateFilters) Active() bool {
+	return f.Refundable || len(f.Boards) > 0 || f.MinPrice > 0 || f.MaxPrice > 0 || f.PaymentType != "" || f.Packaging != nil || f.RateClass != ""
 }
 
 // HotelSearchServiceResponse represents the response for HotelSearch Service
@@ -24,19 +69,110 @@ type HotelSearchServiceResponse struct {
 	HotelPrices      []HotelPrice
 	SupplierResponse string
 	SupplierRequest  string
+	// Warnings reports the requested hotel IDs that could not be searched and the hotels left out
+	Warnings []string
+}
+
+// FlexibleSearchServiceParams represents a search for every stay of Nights nights that fits
+// between WindowStart and WindowEnd
+type FlexibleSearchServiceParams struct {
+	WindowStart string
+	WindowEnd   string
+	Nights      int
+	HotelIDs    []string
+	Destination string
+	Geolocation *Geolocation
+	Currency    string
+	Occupancies []Occupancy
+	Filters     RateFilters
+}
+
+// FlexibleSearchServiceResponse represents the price calendars of a flexible search; FailedCheckIns lists
+// the check-in dates whose supplier search failed
+type FlexibleSearchServiceResponse struct {
+	Calendars      []HotelCalendar
+	FailedCheckIns []string
+	Warnings       []string
 }
 
 // HotelPriceResponse represents the top-level response structure
 type HotelPriceResponse struct {
 	Data     []HotelPrice `json:"data"`
 	Supplier Supplier     `json:"supplier"`
+	// NextCursor requests the next page of a paged search; it is empty on the last page
+	NextCursor string   `json:"nextCursor,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
 }
 
-// HotelPrice represents individual hotel price information
+// HotelPrice represents individual hotel price information. Price is the total for the stay.
 type HotelPrice struct {
-	HotelID  string  `json:"hotelId"`
-	Currency string  `json:"currency"`
-	Price    float64 `json:"price"`
+	HotelID string `json:"hotelId"`
+	Name    string `json:"name,omitempty"`
+	// Stars and Images come from the synced hotel content, when the hotel has been synced
+	Stars    int          `json:"stars,omitempty"`
+	Images   []HotelImage `json:"images,omitempty"`
+	Currency string       `json:"currency"`
+	Price    float64      `json:"price"`
+	Nights   int          `json:"nights"`
+	Total    float64      `json:"total"`
+	PerNight float64      `json:"perNight"`
+	// DailyRates is the nightly breakdown, when the supplier provides one
+	DailyRates []DailyPrice   `json:"dailyRates,omitempty"`
+	Pricing    PriceBreakdown `json:"pricing"`
+	// Cancellation is the policy of the priced rate, when the supplier provides one
+	Cancellation *CancellationTerms `json:"cancellation,omitempty"`
+	// Board is the meal plan of the priced rate, when the supplier provides one
+	Board     Board  `json:"board,omitempty"`
+	BoardName string `json:"boardName,omitempty"`
+	// Distance from the searched point, in the searched unit, for geolocation searches
+	Distance *float64 `json:"distance,omitempty"`
+	// SupplierPrice and SupplierCurrency are the supplier price the stay price was converted from, and
+	// AppliedRate the rate used, spread included; they are kept for audit
+	SupplierPrice    float64 `json:"supplierPrice,omitempty"`
+	SupplierCurrency string  `json:"supplierCurrency,omitempty"`
+	AppliedRate      float64 `json:"appliedRate,omitempty"`
+	// ChargePrice and ChargeCurrency are what the guest is charged. When charging in the supplier currency
+	// they are the marked up supplier price and Price is for display only.
+	ChargePrice    float64 `json:"chargePrice,omitempty"`
+	ChargeCurrency string  `json:"chargeCurrency,omitempty"`
+}
+
+// CancellationTerms represents the liteAPI cancellation policy of a rate. A refundable rate can be cancelled
+// for free until RefundableUntil; afterwards the penalty of the latest tier already started applies.
+// Timestamps are RFC 3339, in the hotel's time zone when it is known.
+type CancellationTerms struct {
+	Refundable      bool                  `json:"refundable"`
+	RefundableUntil string                `json:"refundableUntil,omitempty"`
+	Penalties       []CancellationPenalty `json:"penalties,omitempty"`
+}
+
+// CancellationPenalty represents the amount charged for cancelling from a point in time on
+type CancellationPenalty struct {
+	From   string  `json:"from"`
+	Amount float64 `json:"amount"`
+}
+
+// PriceBreakdown represents what the guest pays: Base plus IncludedTaxes is the stay price paid on booking,
+// PayableAtProperty is collected by the hotel, and Total is everything together
+type PriceBreakdown struct {
+	Base              float64     `json:"base"`
+	IncludedTaxes     float64     `json:"includedTaxes"`
+	PayableAtProperty float64     `json:"payableAtProperty"`
+	Total             float64     `json:"total"`
+	Taxes             []TaxAmount `json:"taxes,omitempty"`
+}
+
+// TaxAmount represents one tax or fee converted into the requested currency
+type TaxAmount struct {
+	Type     string  `json:"type,omitempty"`
+	Included bool    `json:"included"`
+	Amount   float64 `json:"amount"`
+}
+
+// DailyPrice represents the price of the night starting on Date
+type DailyPrice struct {
+	Date  string  `json:"date"`
+	Price float64 `json:"price"`
 }
 
 // Supplier contains the request and response details
@@ -44,3 +180,25 @@ type Supplier struct {
 	Request  string `json:"request"`
 	Response string `json:"response"`
 }
+
+// FlexiblePriceResponse represents the response of a flexible-date search
+type FlexiblePriceResponse struct {
+	Data           []HotelCalendar `json:"data"`
+	FailedCheckIns []string        `json:"failedCheckIns,omitempty"`
+	Warnings       []string        `json:"warnings,omitempty"`
+}
+
+// HotelCalendar represents the price of each candidate stay at a hotel
+type HotelCalendar struct {
+	HotelID  string          `json:"hotelId"`
+	Currency string          `json:"currency"`
+	Cheapest CalendarPrice   `json:"cheapest"`
+	Calendar []CalendarPrice `json:"calendar"`
+}
+
+// CalendarPrice represents the price of one stay
+type CalendarPrice struct {
+	CheckIn  string  `json:"checkIn"`
+	CheckOut string  `json:"checkOut"`
+	Price    float64 `json:"price"`
+}
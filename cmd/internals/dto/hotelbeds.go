@@ -17,11 +17,70 @@ type Hotels struct {
 }
 
 type Hotel struct {
-	Code     int    `json:"code"`
-	Name     string `json:"name"`
-	MinRate  string `json:"minRate"`
-	MaxRate  string `json:"maxRate"`
+	Code            int    `json:"code"`
+	Name            string `json:"name"`
+	DestinationCode string `json:"destinationCode,omitempty"`
+	Latitude        string `json:"latitude,omitempty"`
+	Longitude       string `json:"longitude,omitempty"`
+	MinRate         string `json:"minRate"`
+	MaxRate         string `json:"maxRate"`
+	Currency        string `json:"currency"`
+	Rooms           []Room `json:"rooms,omitempty"`
+}
+
+type Room struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Rates []Rate `json:"rates"`
+}
+
+type Rate struct {
+	RateKey   string `json:"rateKey"`
+	RateClass string `json:"rateClass"`
+	Net       string `json:"net"`
+	BoardCode string `json:"boardCode"`
+	BoardName string `json:"boardName,omitempty"`
+	// PaymentType is AT_WEB when liteAPI collects the payment and AT_HOTEL when the hotel does
+	PaymentType string      `json:"paymentType,omitempty"`
+	Packaging   bool        `json:"packaging,omitempty"`
+	DailyRates  []DailyRate `json:"dailyRates,omitempty"`
+	Taxes       *Taxes      `json:"taxes,omitempty"`
+	// CancellationPolicies are the penalties charged when cancelling from each date on
+	CancellationPolicies []CancellationPolicy `json:"cancellationPolicies,omitempty"`
+}
+
+// CancellationPolicy represents a Hotelbeds cancellation penalty; From is an ISO 8601 timestamp
+type CancellationPolicy struct {
+	Amount string `json:"amount"`
+	From   string `json:"from"`
+}
+
+const (
+	RateClassRefundable    = "NOR"
+	RateClassNonRefundable = "NRF"
+
+	PaymentTypeAtWeb   = "AT_WEB"
+	PaymentTypeAtHotel = "AT_HOTEL"
+)
+
+// Taxes represents the taxes and fees of a rate. Included taxes are part of the net price;
+// the others are paid by the guest at the property.
+type Taxes struct {
+	Taxes       []Tax `json:"taxes"`
+	AllIncluded bool  `json:"allIncluded"`
+}
+
+type Tax struct {
+	Included bool   `json:"included"`
+	Amount   string `json:"amount"`
 	Currency string `json:"currency"`
+	Type     string `json:"type,omitempty"`
+}
+
+// DailyRate represents the net price of one night; Offset 1 is the check-in night
+type DailyRate struct {
+	Offset   int    `json:"offset"`
+	DailyNet string `json:"dailyNet"`
 }
 
 func (h *Hotel) GetStringifiedHotelCode() string {
@@ -39,9 +98,13 @@ func (h *Hotel) GetPrice() (float64, error) {
 }
 
 type HotelBedsSearchRequest struct {
-	Stay        Stay         `json:"stay"`
-	Occupancies []Occupancy  `json:"occupancies"`
-	Hotels      HotelsFilter `json:"hotels"`
+	Stay        Stay          `json:"stay"`
+	Occupancies []Occupancy   `json:"occupancies"`
+	Hotels      *HotelsFilter `json:"hotels,omitempty"`
+	Destination *Destination  `json:"destination,omitempty"`
+	Geolocation *Geolocation  `json:"geolocation,omitempty"`
+	Boards      *BoardsFilter `json:"boards,omitempty"`
+	Filter      *SearchFilter `json:"filter,omitempty"`
 }
 
 type Stay struct {
@@ -58,3 +121,61 @@ type Occupancy struct {
 type HotelsFilter struct {
 	Hotel []int `json:"hotel"`
 }
+
+type Destination struct {
+	Code string `json:"code"`
+}
+
+// Geolocation represents a circle of Radius Unit around a point; Unit is km or mi
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Radius    float64 `json:"radius"`
+	Unit      string  `json:"unit"`
+}
+
+const (
+	UnitKilometers = "km"
+	UnitMiles      = "mi"
+)
+
+// SearchFilter represents the Hotelbeds availability filter; MinRate and MaxRate bound the net rate in
+// the account currency
+type SearchFilter struct {
+	PaymentType string  `json:"paymentType,omitempty"`
+	Packaging   *bool   `json:"packaging,omitempty"`
+	MinRate     float64 `json:"minRate,omitempty"`
+	MaxRate     float64 `json:"maxRate,omitempty"`
+}
+
+// BoardsFilter restricts the rates to the listed board codes, or excludes them when Included is false
+type BoardsFilter struct {
+	Board    []string `json:"board"`
+	Included bool     `json:"included"`
+}
+
+// CheapestRate returns the rate with the lowest net price among those keep accepts; with a nil keep
+// it is the rate Hotelbeds reports as minRate
+func (h *Hotel) CheapestRate(keep func(Rate) bool) (Rate, bool) {
+	var cheapest Rate
+	lowest, found := 0.0, false
+
+	for _, room := range h.Rooms {
+		for _, rate := range room.Rates {
+			if keep != nil && !keep(rate) {
+				continue
+			}
+
+			net, err := strconv.ParseFloat(rate.Net, 64)
+			if err != nil {
+				continue
+			}
+
+			if !found || net < lowest {
+				cheapest, lowest, found = rate, net, true
+			}
+		}
+	}
+
+	return cheapest, found
+}
@@ -0,0 +1,87 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Search job statuses; a job is completed once every batch was searched, and failed when every batch failed
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// SearchJobRequest represents the body of an asynchronous search; its fields are the /hotels query params,
+// with hotelIds and occupancies as JSON arrays
+type SearchJobRequest struct {
+	CheckIn          string      `json:"checkin" binding:"required"`
+	CheckOut         string      `json:"checkout" binding:"required"`
+	Currency         string      `json:"currency"`
+	GuestNationality string      `json:"guestNationality"`
+	Occupancies      []Occupancy `json:"occupancies" binding:"required"`
+	HotelIds         []string    `json:"hotelIds"`
+	Destination      string      `json:"destination"`
+	Latitude         *float64    `json:"latitude"`
+	Longitude        *float64    `json:"longitude"`
+	Radius           float64     `json:"radius"`
+	Unit             string      `json:"unit"`
+	Refundable       bool        `json:"refundable"`
+	Boards           []string    `json:"boards"`
+	MinPrice         float64     `json:"minPrice"`
+	MaxPrice         float64     `json:"maxPrice"`
+	PaymentType      string      `json:"paymentType"`
+	Packaging        *bool       `json:"packaging"`
+	RateClass        string      `json:"rateClass"`
+	Sort             string      `json:"sort"`
+}
+
+// QueryParams returns the request as the /hotels query params, so both are validated alike
+func (r SearchJobRequest) QueryParams() HotelSearchQueryParams {
+	occupancies, _ := json.Marshal(r.Occupancies)
+
+	return HotelSearchQueryParams{
+		CheckIn:          r.CheckIn,
+		CheckOut:         r.CheckOut,
+		Currency:         r.Currency,
+		GuestNationality: r.GuestNationality,
+		Occupancies:      string(occupancies),
+		HotelIds:         strings.Join(r.HotelIds, ","),
+		Destination:      r.Destination,
+		Latitude:         r.Latitude,
+		Longitude:        r.Longitude,
+		Radius:           r.Radius,
+		Unit:             r.Unit,
+		Refundable:       r.Refundable,
+		Boards:           strings.Join(r.Boards, ","),
+		MinPrice:         r.MinPrice,
+		MaxPrice:         r.MaxPrice,
+		PaymentType:      r.PaymentType,
+		Packaging:        r.Packaging,
+		RateClass:        r.RateClass,
+		Sort:             r.Sort,
+	}
+}
+
+// SearchJob represents an asynchronous search. The hotels are searched in batches; Data holds the hotels
+// of the batches searched so far, in the requested order.
+type SearchJob struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	// Progress is the share of the batches searched, from 0 to 1
+	Progress      float64      `json:"progress"`
+	Batches       int          `json:"batches"`
+	BatchesDone   int          `json:"batchesDone"`
+	BatchesFailed int          `json:"batchesFailed"`
+	Data          []HotelPrice `json:"data"`
+	Warnings      []string     `json:"warnings,omitempty"`
+	CreatedAt     time.Time    `json:"createdAt"`
+	ExpiresAt     time.Time    `json:"expiresAt"`
+	// TenantID binds the job to the tenant that submitted it; it is empty when authentication is disabled
+	TenantID string `json:"-"`
+	// BatchData holds the hotels of each searched batch by batch index, so Data keeps the requested order
+	// whichever batch finishes first
+	BatchData [][]HotelPrice `json:"-"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "fmt"
+
+// SortOrder represents the order of the hotels in a search response
+type SortOrder string
+
+const (
+	SortPriceAsc  SortOrder = "price"
+	SortPriceDesc SortOrder = "-price"
+	SortHotelID   SortOrder = "hotelId"
+	SortDistance  SortOrder = "distance"
+	SortName      SortOrder = "name"
+)
+
+// ParseSortOrder validates a sort query param; an empty value keeps the supplier order
+func ParseSortOrder(value string) (SortOrder, error) {
+	switch order := SortOrder(value); order {
+	case "", SortPriceAsc, SortPriceDesc, SortHotelID, SortDistance, SortName:
+		return order, nil
+	default:
+		return "", fmt.Errorf("sort must be one of %v, %v, %v, %v or %v", SortPriceAsc, SortPriceDesc, SortHotelID, SortDistance, SortName)
+	}
+}
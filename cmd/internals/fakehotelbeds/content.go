@@ -0,0 +1,41 @@
+package fakehotelbeds
+
+import "fmt"
+
+// destinationCities names the seeded destinations in the generated content
+var destinationCities = map[string]struct{ city, country string }{
+	"PMI": {"PALMA DE MALLORCA", "ES"},
+	"BCN": {"BARCELONA", "ES"},
+	"ROM": {"ROME", "IT"},
+	"LON": {"LONDON", "GB"},
+	"NYC": {"NEW YORK", "US"},
+}
+
+// buildContent generates deterministic static content for a seeded hotel
+func buildContent(h SeedHotel) contentHotel {
+	location := destinationCities[h.DestinationCode]
+
+	// Content API image paths group hotels by the leading digits of their six-digit code
+	dir := fmt.Sprintf("%02d/%06d", h.Code/10000, h.Code)
+
+	return contentHotel{
+		Code:            h.Code,
+		Name:            contentText{Content: h.Name},
+		Description:     contentText{Content: fmt.Sprintf("%v is a %v hotel in %v.", h.Name, h.CategoryCode, location.city)},
+		CountryCode:     location.country,
+		DestinationCode: h.DestinationCode,
+		CategoryCode:    h.CategoryCode,
+		Coordinates:     coordinates{Latitude: h.Latitude, Longitude: h.Longitude},
+		Address:         contentText{Content: fmt.Sprintf("Calle Fake %d", h.Code%100)},
+		City:            contentText{Content: location.city},
+		Facilities: []contentFacility{
+			{FacilityCode: 260, FacilityGroupCode: 60, Description: contentText{Content: "Wi-fi"}},
+			{FacilityCode: 363, FacilityGroupCode: 73, Description: contentText{Content: "Outdoor swimming pool"}},
+		},
+		Images: []contentImage{
+			{ImageTypeCode: "HAB", Path: fmt.Sprintf("%v/%06da_hb_ro_001.jpg", dir, h.Code), Order: 1, VisualOrder: 2},
+			{ImageTypeCode: "GEN", Path: fmt.Sprintf("%v/%06da_hb_a_001.jpg", dir, h.Code), Order: 1, VisualOrder: 0},
+			{ImageTypeCode: "PIS", Path: fmt.Sprintf("%v/%06da_hb_p_001.jpg", dir, h.Code), Order: 2, VisualOrder: 1},
+		},
+	}
+}
@@ -0,0 +1,71 @@
+package fakehotelbeds
+
+import (
+	"sync"
+	"time"
+)
+
+// Fault represents a misbehaviour applied to a request. Latency is applied first; then either
+// Status is returned with a Hotelbeds error body, or, with Malformed, the body is truncated JSON.
+type Fault struct {
+	Latency   Duration `json:"latency"`
+	Status    int      `json:"status"`
+	Malformed bool     `json:"malformed"`
+	// Times is how many consecutive requests the fault applies to; zero means every following request
+	Times int `json:"times"`
+}
+
+// Duration accepts "250ms" style strings in fault scripts
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	d.Duration = parsed
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// faultScript hands out faults in order to successive supplier requests
+type faultScript struct {
+	mu     sync.Mutex
+	faults []Fault
+	used   int
+}
+
+func (s *faultScript) set(faults []Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append([]Fault{}, faults...)
+	s.used = 0
+}
+
+// next returns the fault for the current request, if any
+func (s *faultScript) next() (Fault, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.faults) == 0 {
+		return Fault{}, false
+	}
+
+	fault := s.faults[0]
+	if fault.Times > 0 {
+		s.used++
+		if s.used >= fault.Times {
+			s.faults = s.faults[1:]
+			s.used = 0
+		}
+	}
+
+	return fault, true
+}
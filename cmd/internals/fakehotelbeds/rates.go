@@ -0,0 +1,251 @@
+package fakehotelbeds
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var boardNames = map[string]string{
+	"RO": "ROOM ONLY",
+	"BB": "BED AND BREAKFAST",
+	"HB": "HALF BOARD",
+	"FB": "FULL BOARD",
+	"AI": "ALL INCLUSIVE",
+}
+
+// boardSurcharges are added per person per night
+var boardSurcharges = map[string]float64{
+	"RO": 0,
+	"BB": 12,
+	"HB": 30,
+	"FB": 45,
+	"AI": 70,
+}
+
+// rateKeyParts represents everything needed to re-price a rate, so checkrate and booking are stateless
+type rateKeyParts struct {
+	CheckIn   string
+	CheckOut  string
+	HotelCode int
+	RoomCode  string
+	BoardCode string
+	RateClass string
+	Rooms     int
+	Adults    int
+	Children  int
+}
+
+func (p rateKeyParts) String() string {
+	return strings.Join([]string{
+		p.CheckIn,
+		p.CheckOut,
+		strconv.Itoa(p.HotelCode),
+		p.RoomCode,
+		p.BoardCode,
+		p.RateClass,
+		fmt.Sprintf("%d~%d~%d", p.Rooms, p.Adults, p.Children),
+	}, "|")
+}
+
+func parseRateKey(rateKey string) (rateKeyParts, error) {
+	var p rateKeyParts
+
+	fields := strings.Split(rateKey, "|")
+	if len(fields) != 7 {
+		return p, errors.New("malformed rateKey")
+	}
+
+	code, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return p, errors.New("malformed rateKey")
+	}
+
+	if _, err := fmt.Sscanf(fields[6], "%d~%d~%d", &p.Rooms, &p.Adults, &p.Children); err != nil {
+		return p, errors.New("malformed rateKey")
+	}
+
+	p.CheckIn, p.CheckOut, p.HotelCode = fields[0], fields[1], code
+	p.RoomCode, p.BoardCode, p.RateClass = fields[3], fields[4], fields[5]
+
+	return p, nil
+}
+
+// nights returns the length of stay, validating the dates
+func nights(checkIn, checkOut string) (int, error) {
+	in, err := time.Parse(dateLayout, checkIn)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkIn: %v", checkIn)
+	}
+
+	out, err := time.Parse(dateLayout, checkOut)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkOut: %v", checkOut)
+	}
+
+	n := int(out.Sub(in).Hours() / 24)
+	if n <= 0 {
+		return 0, errors.New("checkOut must be after checkIn")
+	}
+
+	return n, nil
+}
+
+// variation returns a deterministic factor in [0.9, 1.1) for the given inputs
+func variation(parts ...string) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(strings.Join(parts, "|")))
+	return 0.9 + float64(h.Sum32()%2000)/10000
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(round2(v), 'f', 2, 64)
+}
+
+// buildRate prices one room/board/rate class combination deterministically
+func buildRate(h SeedHotel, r SeedRoom, p rateKeyParts, now time.Time) (rate, error) {
+	n, err := nights(p.CheckIn, p.CheckOut)
+	if err != nil {
+		return rate{}, err
+	}
+
+	multiplier := r.Multiplier
+	if multiplier == 0 {
+		multiplier = 1
+	}
+
+	in, _ := time.Parse(dateLayout, p.CheckIn)
+	guests := float64(p.Adults) + 0.5*float64(p.Children)
+	rooms := math.Max(1, float64(p.Rooms))
+
+	daily := make([]dailyRate, 0, n)
+	total := 0.0
+	for i := 0; i < n; i++ {
+		night := in.AddDate(0, 0, i).Format(dateLayout)
+		price := h.BasePrice*multiplier*rooms*variation(strconv.Itoa(h.Code), r.Code, night) +
+			boardSurcharges[p.BoardCode]*guests
+		if p.RateClass == "NRF" {
+			price *= 0.85
+		}
+		price = round2(price)
+		total += price
+		daily = append(daily, dailyRate{Offset: i + 1, DailyNet: formatAmount(price)})
+	}
+
+	result := rate{
+		RateKey:     p.String(),
+		RateClass:   p.RateClass,
+		RateType:    "BOOKABLE",
+		Net:         formatAmount(total),
+		Allotment:   5,
+		PaymentType: "AT_WEB",
+		BoardCode:   p.BoardCode,
+		BoardName:   boardNames[p.BoardCode],
+		Rooms:       p.Rooms,
+		Adults:      p.Adults,
+		Children:    p.Children,
+		DailyRates:  daily,
+		Taxes: &taxes{
+			AllIncluded: false,
+			Taxes: []tax{
+				{Included: true, Amount: formatAmount(total * 0.1), Currency: h.Currency, Type: "TAX"},
+				{Included: false, Amount: formatAmount(2.5 * float64(n) * float64(p.Adults)), Currency: h.Currency, Type: "TAXESANDFEES"},
+			},
+		},
+	}
+
+	// Non-refundable rates are penalised in full from today; the others charge
+	// the first night from three days before arrival
+	if p.RateClass == "NRF" {
+		result.CancellationPolicies = []cancellationPolicy{
+			{Amount: result.Net, From: now.UTC().Truncate(24 * time.Hour).Format(time.RFC3339)},
+		}
+	} else {
+		result.CancellationPolicies = []cancellationPolicy{
+			{Amount: daily[0].DailyNet, From: in.AddDate(0, 0, -3).Add(23*time.Hour + 59*time.Minute).Format(time.RFC3339)},
+		}
+	}
+
+	return result, nil
+}
+
+// buildHotel returns the hotel with every room, board and rate class priced for the stay
+func buildHotel(h SeedHotel, checkIn, checkOut string, occ occupancy, boards map[string]bool, now time.Time) (hotel, error) {
+	result := hotel{
+		Code:            h.Code,
+		Name:            h.Name,
+		CategoryCode:    h.CategoryCode,
+		DestinationCode: h.DestinationCode,
+		Latitude:        strconv.FormatFloat(h.Latitude, 'f', -1, 64),
+		Longitude:       strconv.FormatFloat(h.Longitude, 'f', -1, 64),
+		Currency:        h.Currency,
+	}
+
+	minRate, maxRate := math.Inf(1), math.Inf(-1)
+	for _, r := range h.Rooms {
+		hotelRoom := room{Code: r.Code, Name: r.Name}
+		for _, board := range h.Boards {
+			if boards != nil && !boards[board] {
+				continue
+			}
+
+			for _, rateClass := range []string{"NOR", "NRF"} {
+				rt, err := buildRate(h, r, rateKeyParts{
+					CheckIn:   checkIn,
+					CheckOut:  checkOut,
+					HotelCode: h.Code,
+					RoomCode:  r.Code,
+					BoardCode: board,
+					RateClass: rateClass,
+					Rooms:     max(1, occ.Rooms),
+					Adults:    occ.Adults,
+					Children:  occ.Children,
+				}, now)
+				if err != nil {
+					return result, err
+				}
+
+				net, _ := strconv.ParseFloat(rt.Net, 64)
+				minRate, maxRate = math.Min(minRate, net), math.Max(maxRate, net)
+				hotelRoom.Rates = append(hotelRoom.Rates, rt)
+			}
+		}
+
+		if len(hotelRoom.Rates) > 0 {
+			result.Rooms = append(result.Rooms, hotelRoom)
+		}
+	}
+
+	if len(result.Rooms) > 0 {
+		result.MinRate = formatAmount(minRate)
+		result.MaxRate = formatAmount(maxRate)
+	}
+
+	return result, nil
+}
+
+// distanceKm returns the great-circle distance between two points
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func parseAmount(v string) (float64, error) {
+	return strconv.ParseFloat(v, 64)
+}
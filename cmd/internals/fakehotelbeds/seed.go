@@ -0,0 +1,92 @@
+package fakehotelbeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Seed represents the hotel inventory the fake serves rates for
+type Seed struct {
+	Hotels []SeedHotel `json:"hotels"`
+}
+
+// SeedHotel represents one hotel of the inventory; BasePrice is the nightly price of the cheapest room for two adults
+type SeedHotel struct {
+	Code            int        `json:"code"`
+	Name            string     `json:"name"`
+	CategoryCode    string     `json:"categoryCode"`
+	DestinationCode string     `json:"destinationCode"`
+	Latitude        float64    `json:"latitude"`
+	Longitude       float64    `json:"longitude"`
+	Currency        string     `json:"currency"`
+	BasePrice       float64    `json:"basePrice"`
+	Rooms           []SeedRoom `json:"rooms"`
+	Boards          []string   `json:"boards"`
+}
+
+// SeedRoom represents a room type; the price multiplier is applied to the hotel's base price
+type SeedRoom struct {
+	Code       string  `json:"code"`
+	Name       string  `json:"name"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+// LoadSeed reads a seed file
+func LoadSeed(path string) (Seed, error) {
+	var seed Seed
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return seed, fmt.Errorf("failed to read seed file: %w", err)
+	}
+
+	if err := json.Unmarshal(content, &seed); err != nil {
+		return seed, fmt.Errorf("failed to parse seed file: %w", err)
+	}
+
+	for i, h := range seed.Hotels {
+		if h.Code == 0 || h.Currency == "" || h.BasePrice <= 0 {
+			return seed, fmt.Errorf("seed hotel %d: code, currency and a positive basePrice are required", i)
+		}
+	}
+
+	return seed, nil
+}
+
+// DefaultSeed returns a small inventory used when no seed file is given
+func DefaultSeed() Seed {
+	return Seed{
+		Hotels: []SeedHotel{
+			{
+				Code:            1234,
+				Name:            "Hotel Playa Sol",
+				CategoryCode:    "4EST",
+				DestinationCode: "PMI",
+				Latitude:        39.5696,
+				Longitude:       2.6502,
+				Currency:        "EUR",
+				BasePrice:       120,
+				Rooms: []SeedRoom{
+					{Code: "DBL.ST", Name: "DOUBLE STANDARD", Multiplier: 1},
+					{Code: "DBL.SU", Name: "DOUBLE SUPERIOR", Multiplier: 1.4},
+				},
+				Boards: []string{"RO", "BB", "HB"},
+			},
+			{
+				Code:            5678,
+				Name:            "Grand Hotel Centrale",
+				CategoryCode:    "5EST",
+				DestinationCode: "ROM",
+				Latitude:        41.9028,
+				Longitude:       12.4964,
+				Currency:        "EUR",
+				BasePrice:       210,
+				Rooms: []SeedRoom{
+					{Code: "DBL.ST", Name: "DOUBLE STANDARD", Multiplier: 1},
+				},
+				Boards: []string{"RO", "BB"},
+			},
+		},
+	}
+}
@@ -0,0 +1,501 @@
+package fakehotelbeds
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+)
+
+const (
+	PathAvailability = "/hotel-api/1.0/hotels"
+	PathCheckRates   = "/hotel-api/1.0/checkrates"
+	PathBookings     = "/hotel-api/1.0/bookings"
+	PathStatus       = "/hotel-api/1.0/status"
+	PathContent      = "/hotel-content-api/1.0/hotels"
+	PathFaults       = "/_fake/faults"
+
+	defaultSignatureWindow = 5 * time.Minute
+)
+
+// Options represents the fake's credentials, inventory and initial fault script
+type Options struct {
+	APIKey          string
+	Secret          string
+	Seed            Seed
+	Faults          []Fault
+	SignatureWindow time.Duration
+}
+
+// Server is an in-process stand-in for the Hotelbeds Booking and Content APIs
+type Server struct {
+	apiKey          string
+	secret          string
+	signatureWindow time.Duration
+	hotels          map[int]SeedHotel
+	codes           []int
+	faults          faultScript
+	bookings        atomic.Int64
+	now             func() time.Time
+}
+
+func New(opts Options) *Server {
+	s := &Server{
+		apiKey:          opts.APIKey,
+		secret:          opts.Secret,
+		signatureWindow: opts.SignatureWindow,
+		hotels:          make(map[int]SeedHotel, len(opts.Seed.Hotels)),
+		now:             time.Now,
+	}
+
+	if s.signatureWindow == 0 {
+		s.signatureWindow = defaultSignatureWindow
+	}
+
+	for _, h := range opts.Seed.Hotels {
+		s.hotels[h.Code] = h
+		s.codes = append(s.codes, h.Code)
+	}
+	sort.Ints(s.codes)
+
+	s.faults.set(opts.Faults)
+
+	return s
+}
+
+// SetFaults replaces the fault script; the next request gets the first fault
+func (s *Server) SetFaults(faults []Fault) {
+	s.faults.set(faults)
+}
+
+// Handler returns the HTTP handler serving the supplier endpoints and the fault admin endpoint
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(PathAvailability, s.supplier(http.MethodPost, s.availability))
+	mux.Handle(PathCheckRates, s.supplier(http.MethodPost, s.checkRates))
+	mux.Handle(PathBookings, s.supplier(http.MethodPost, s.book))
+	mux.Handle(PathStatus, s.supplier(http.MethodGet, s.status))
+	mux.Handle(PathContent, s.supplier(http.MethodGet, s.content))
+	mux.HandleFunc(PathFaults, s.faultsAdmin)
+
+	return mux
+}
+
+type supplierHandler func(query url.Values, body []byte) (int, any)
+
+// supplier wraps an endpoint with method, signature, fault and gzip handling
+func (s *Server) supplier(method string, handle supplierHandler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			s.writeError(w, r, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", fmt.Sprintf("use %v", method))
+			return
+		}
+
+		if err := s.verifySignature(r); err != nil {
+			s.writeError(w, r, http.StatusUnauthorized, "AUTHORIZATION_FAILED", err.Error())
+			return
+		}
+
+		if fault, ok := s.faults.next(); ok {
+			if fault.Latency.Duration > 0 {
+				select {
+				case <-time.After(fault.Latency.Duration):
+				case <-r.Context().Done():
+					return
+				}
+			}
+
+			switch {
+			case fault.Status != 0:
+				s.writeError(w, r, fault.Status, "INJECTED_FAULT", http.StatusText(fault.Status))
+				return
+			case fault.Malformed:
+				s.write(w, r, http.StatusOK, []byte(`{"auditData":{"processTime":"1"},"hotels":{"hotels":[{"code":`))
+				return
+			}
+		}
+
+		body, err := readBody(r)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		status, response := handle(r.URL.Query(), body)
+		if e, ok := response.(errorDetail); ok {
+			s.writeError(w, r, status, e.Code, e.Message)
+			return
+		}
+
+		s.writeJSON(w, r, status, response)
+	})
+}
+
+// verifySignature checks the Api-key header and that X-Signature is SHA-256(apiKey + secret + timestamp)
+// for a timestamp within the signature window
+func (s *Server) verifySignature(r *http.Request) error {
+	apiKey := r.Header.Get(util.HeaderApiKey)
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(s.apiKey)) != 1 {
+		return fmt.Errorf("invalid %v header", util.HeaderApiKey)
+	}
+
+	signature := strings.ToLower(r.Header.Get(util.HeaderSignature))
+	now := s.now().Unix()
+	window := int64(s.signatureWindow.Seconds())
+	for ts := now - window; ts <= now+window; ts++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s%s%d", s.apiKey, s.secret, ts)))
+		if subtle.ConstantTimeCompare([]byte(signature), []byte(hex.EncodeToString(sum[:]))) == 1 {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid %v header", util.HeaderSignature)
+}
+
+func (s *Server) availability(_ url.Values, body []byte) (int, any) {
+	var req availabilityRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_REQUEST", Message: "malformed JSON body"}
+	}
+
+	if _, err := nights(req.Stay.CheckIn, req.Stay.CheckOut); err != nil {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: err.Error()}
+	}
+
+	if len(req.Occupancies) == 0 {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: "occupancies are required"}
+	}
+
+	codes, err := s.selectHotels(req)
+	if err != nil {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: err.Error()}
+	}
+
+	var boards map[string]bool
+	if req.Boards != nil && len(req.Boards.Board) > 0 {
+		boards = map[string]bool{}
+		included := map[string]bool{}
+		for _, b := range req.Boards.Board {
+			included[b] = true
+		}
+		for _, b := range boardCodes() {
+			if included[b] == req.Boards.Included {
+				boards[b] = true
+			}
+		}
+	}
+
+	result := availabilityResponse{
+		AuditData: s.auditData(),
+		Hotels: hotelsBlock{
+			CheckIn:  req.Stay.CheckIn,
+			CheckOut: req.Stay.CheckOut,
+		},
+	}
+
+	for _, code := range codes {
+		h, err := buildHotel(s.hotels[code], req.Stay.CheckIn, req.Stay.CheckOut, req.Occupancies[0], boards, s.now())
+		if err != nil {
+			return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: err.Error()}
+		}
+
+		if len(h.Rooms) > 0 {
+			result.Hotels.Hotels = append(result.Hotels.Hotels, h)
+		}
+	}
+	result.Hotels.Total = len(result.Hotels.Hotels)
+
+	return http.StatusOK, result
+}
+
+// selectHotels resolves the hotel list, destination or geolocation search to seeded hotel codes
+func (s *Server) selectHotels(req availabilityRequest) ([]int, error) {
+	var codes []int
+
+	switch {
+	case req.Hotels != nil && len(req.Hotels.Hotel) > 0:
+		for _, code := range req.Hotels.Hotel {
+			if _, ok := s.hotels[code]; ok {
+				codes = append(codes, code)
+			}
+		}
+	case req.Destination != nil && req.Destination.Code != "":
+		for _, code := range s.codes {
+			if s.hotels[code].DestinationCode == req.Destination.Code {
+				codes = append(codes, code)
+			}
+		}
+	case req.Geolocation != nil:
+		radius := req.Geolocation.Radius
+		if strings.EqualFold(req.Geolocation.Unit, "mi") {
+			radius *= 1.609344
+		}
+		for _, code := range s.codes {
+			h := s.hotels[code]
+			if distanceKm(req.Geolocation.Latitude, req.Geolocation.Longitude, h.Latitude, h.Longitude) <= radius {
+				codes = append(codes, code)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("one of hotels, destination or geolocation is required")
+	}
+
+	return codes, nil
+}
+
+func (s *Server) checkRates(_ url.Values, body []byte) (int, any) {
+	var req checkRateRequest
+	if err := json.Unmarshal(body, &req); err != nil || len(req.Rooms) == 0 {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_REQUEST", Message: "rooms with a rateKey are required"}
+	}
+
+	h, status, errDetail := s.priceRateKeys(req.Rooms)
+	if errDetail != nil {
+		return status, *errDetail
+	}
+
+	return http.StatusOK, checkRateResponse{AuditData: s.auditData(), Hotel: h}
+}
+
+func (s *Server) book(_ url.Values, body []byte) (int, any) {
+	var req bookingRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_REQUEST", Message: "malformed JSON body"}
+	}
+
+	if req.Holder.Name == "" || req.Holder.Surname == "" || len(req.Rooms) == 0 {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: "holder and rooms are required"}
+	}
+
+	refs := make([]rateKeyRef, 0, len(req.Rooms))
+	for _, r := range req.Rooms {
+		refs = append(refs, rateKeyRef{RateKey: r.RateKey})
+	}
+
+	h, status, errDetail := s.priceRateKeys(refs)
+	if errDetail != nil {
+		return status, *errDetail
+	}
+
+	return http.StatusOK, bookingResponse{
+		AuditData: s.auditData(),
+		Booking: booking{
+			Reference:       fmt.Sprintf("1-%07d", s.bookings.Add(1)),
+			ClientReference: req.ClientReference,
+			CreationDate:    s.now().UTC().Format(dateLayout),
+			Status:          "CONFIRMED",
+			Holder:          req.Holder,
+			Hotel:           h,
+			TotalNet:        h.TotalNet,
+			Currency:        h.Currency,
+		},
+	}
+}
+
+// priceRateKeys re-prices the rate keys, which must all belong to the same hotel
+func (s *Server) priceRateKeys(refs []rateKeyRef) (hotel, int, *errorDetail) {
+	var result hotel
+	total := 0.0
+
+	for i, ref := range refs {
+		parts, err := parseRateKey(ref.RateKey)
+		if err != nil {
+			return result, http.StatusBadRequest, &errorDetail{Code: "INVALID_DATA", Message: err.Error()}
+		}
+
+		seedHotel, ok := s.hotels[parts.HotelCode]
+		if !ok || (i > 0 && parts.HotelCode != result.Code) {
+			return result, http.StatusBadRequest, &errorDetail{Code: "INVALID_DATA", Message: "unknown hotel in rateKey"}
+		}
+
+		var seedRoom *SeedRoom
+		for j := range seedHotel.Rooms {
+			if seedHotel.Rooms[j].Code == parts.RoomCode {
+				seedRoom = &seedHotel.Rooms[j]
+			}
+		}
+		if seedRoom == nil {
+			return result, http.StatusBadRequest, &errorDetail{Code: "INVALID_DATA", Message: "unknown room in rateKey"}
+		}
+
+		rt, err := buildRate(seedHotel, *seedRoom, parts, s.now())
+		if err != nil {
+			return result, http.StatusBadRequest, &errorDetail{Code: "INVALID_DATA", Message: err.Error()}
+		}
+
+		if i == 0 {
+			result = hotel{
+				Code:            seedHotel.Code,
+				Name:            seedHotel.Name,
+				CategoryCode:    seedHotel.CategoryCode,
+				DestinationCode: seedHotel.DestinationCode,
+				Currency:        seedHotel.Currency,
+			}
+		}
+
+		net, _ := parseAmount(rt.Net)
+		total += net
+		result.Rooms = append(result.Rooms, room{Code: seedRoom.Code, Name: seedRoom.Name, Rates: []rate{rt}})
+	}
+
+	result.TotalNet = formatAmount(total)
+
+	return result, http.StatusOK, nil
+}
+
+func (s *Server) status(_ url.Values, body []byte) (int, any) {
+	return http.StatusOK, map[string]any{
+		"auditData": s.auditData(),
+		"status":    "OK",
+	}
+}
+
+// content serves the Content API hotels endpoint from the seed; hotels are listed by code and paged
+// with the inclusive 1-based from and to positions
+func (s *Server) content(query url.Values, _ []byte) (int, any) {
+	from, to := 1, 100
+	for name, target := range map[string]*int{"from": &from, "to": &to} {
+		if v := query.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: fmt.Sprintf("%v must be a positive number", name)}
+			}
+			*target = n
+		}
+	}
+	if to < from || to-from >= 1000 {
+		return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: "from and to must select between 1 and 1000 hotels"}
+	}
+
+	codes := s.codes
+	if v := query.Get("codes"); v != "" {
+		codes = nil
+		for _, c := range strings.Split(v, ",") {
+			code, err := strconv.Atoi(strings.TrimSpace(c))
+			if err != nil {
+				return http.StatusBadRequest, errorDetail{Code: "INVALID_DATA", Message: "codes must be numbers"}
+			}
+			if _, ok := s.hotels[code]; ok {
+				codes = append(codes, code)
+			}
+		}
+	}
+
+	result := contentResponse{
+		AuditData: s.auditData(),
+		From:      from,
+		To:        to,
+		Total:     len(codes),
+	}
+	for i := from - 1; i < to && i < len(codes); i++ {
+		result.Hotels = append(result.Hotels, buildContent(s.hotels[codes[i]]))
+	}
+
+	return http.StatusOK, result
+}
+
+// faultsAdmin replaces (PUT) or returns (GET) the fault script; it requires no signature
+func (s *Server) faultsAdmin(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		var faults []Fault
+		if err := json.NewDecoder(r.Body).Decode(&faults); err != nil {
+			http.Error(w, "body must be a JSON array of faults", http.StatusBadRequest)
+			return
+		}
+		s.SetFaults(faults)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		s.faults.mu.Lock()
+		faults := append([]Fault{}, s.faults.faults...)
+		s.faults.mu.Unlock()
+		w.Header().Set(util.HeaderContentType, util.ValueApplicationJSON)
+		json.NewEncoder(w).Encode(faults)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) auditData() auditData {
+	return auditData{
+		ProcessTime: "1",
+		Timestamp:   s.now().UTC().Format("2006-01-02 15:04:05.000"),
+		Environment: "[fake]",
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	slog.Debug("fake hotelbeds error", slog.String("path", r.URL.Path), slog.Int("status", status), slog.String("code", code))
+	s.writeJSON(w, r, status, errorResponse{
+		AuditData: s.auditData(),
+		Error:     errorDetail{Code: code, Message: message},
+	})
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.write(w, r, status, body)
+}
+
+// write sends body, gzip encoded when the client accepts it
+func (s *Server) write(w http.ResponseWriter, r *http.Request, status int, body []byte) {
+	w.Header().Set(util.HeaderContentType, util.ValueApplicationJSON)
+
+	if !strings.Contains(r.Header.Get(util.HeaderAcceptEncoding), util.ValueGzip) {
+		w.WriteHeader(status)
+		w.Write(body)
+		return
+	}
+
+	w.Header().Set("Content-Encoding", util.ValueGzip)
+	w.WriteHeader(status)
+	gz := gzip.NewWriter(w)
+	gz.Write(body)
+	gz.Close()
+}
+
+// readBody reads the request body, decoding it when it is gzip encoded
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+
+	reader := io.Reader(r.Body)
+	if r.Header.Get("Content-Encoding") == util.ValueGzip {
+		gz, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	return io.ReadAll(reader)
+}
+
+func boardCodes() []string {
+	codes := make([]string, 0, len(boardNames))
+	for code := range boardNames {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
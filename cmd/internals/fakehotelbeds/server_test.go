@@ -0,0 +1,379 @@
+package fakehotelbeds
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAPIKey = "test-key"
+	testSecret = "test-secret"
+)
+
+func newTestServer(faults ...Fault) (*Server, *httptest.Server) {
+	fake := New(Options{APIKey: testAPIKey, Secret: testSecret, Seed: DefaultSeed(), Faults: faults})
+	return fake, httptest.NewServer(fake.Handler())
+}
+
+func newTestClient(baseURL string) client.HotelBedsClient {
+	return client.NewHotelBedsClient(
+		config.HotelBedsConfig{
+			BaseURL:   baseURL,
+			APIKey:    testAPIKey,
+			APISecret: testSecret,
+			Timeout:   config.Duration{Duration: time.Second * 5},
+		},
+		nil,
+		nil,
+	)
+}
+
+func searchRequest(body any) []byte {
+	b, _ := json.Marshal(body)
+	return b
+}
+
+func signedRequest(method, url string, body []byte, secret string) *http.Request {
+	req, _ := http.NewRequest(method, url, bytes.NewReader(body))
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s%s%d", testAPIKey, secret, time.Now().Unix())))
+	req.Header.Set(util.HeaderApiKey, testAPIKey)
+	req.Header.Set(util.HeaderSignature, hex.EncodeToString(sum[:]))
+	req.Header.Set(util.HeaderContentType, util.ValueApplicationJSON)
+	return req
+}
+
+func TestServer_AvailabilityWithClient(t *testing.T) {
+	_, ts := newTestServer()
+	defer ts.Close()
+
+	request := searchRequest(dto.HotelBedsSearchRequest{
+		Stay:        dto.Stay{CheckIn: "2030-06-01", CheckOut: "2030-06-03"},
+		Occupancies: []dto.Occupancy{{Rooms: 1, Adults: 2}},
+		Hotels:      &dto.HotelsFilter{Hotel: []int{1234, 5678, 9999}},
+	})
+
+	first, err := newTestClient(ts.URL).SearchHotels(context.Background(), request)
+	assert.NoError(t, err)
+
+	second, err := newTestClient(ts.URL).SearchHotels(context.Background(), request)
+	assert.NoError(t, err)
+
+	var firstRates, secondRates availabilityResponse
+	assert.NoError(t, json.Unmarshal(first, &firstRates))
+	assert.NoError(t, json.Unmarshal(second, &secondRates))
+	assert.Equal(t, firstRates.Hotels, secondRates.Hotels, "rates must be deterministic")
+
+	var response dto.HotelbedsResponse
+	assert.NoError(t, json.Unmarshal(first, &response))
+	assert.Equal(t, 2, response.Hotels.Total)
+	assert.Equal(t, 1234, response.Hotels.Hotels[0].Code)
+	assert.Equal(t, "EUR", response.Hotels.Hotels[0].Currency)
+
+	price, err := response.Hotels.Hotels[0].GetPrice()
+	assert.NoError(t, err)
+	assert.Greater(t, price, 0.0)
+}
+
+func TestServer_Search(t *testing.T) {
+	_, ts := newTestServer()
+	defer ts.Close()
+
+	stay := map[string]string{"checkIn": "2030-06-01", "checkOut": "2030-06-02"}
+	occupancies := []map[string]int{{"rooms": 1, "adults": 2}}
+
+	tests := []struct {
+		name           string
+		body           map[string]any
+		expectedStatus int
+		expectedHotels []int
+		expectedBoards []string
+	}{
+		{
+			name:           "By destination",
+			body:           map[string]any{"stay": stay, "occupancies": occupancies, "destination": map[string]string{"code": "ROM"}},
+			expectedStatus: http.StatusOK,
+			expectedHotels: []int{5678},
+		},
+		{
+			name: "By geolocation",
+			body: map[string]any{"stay": stay, "occupancies": occupancies,
+				"geolocation": map[string]any{"latitude": 39.57, "longitude": 2.65, "radius": 5, "unit": "km"}},
+			expectedStatus: http.StatusOK,
+			expectedHotels: []int{1234},
+		},
+		{
+			name: "With boards filter",
+			body: map[string]any{"stay": stay, "occupancies": occupancies,
+				"hotels": map[string][]int{"hotel": {1234}}, "boards": map[string]any{"board": []string{"BB"}, "included": true}},
+			expectedStatus: http.StatusOK,
+			expectedHotels: []int{1234},
+			expectedBoards: []string{"BB"},
+		},
+		{
+			name:           "Without hotels, destination or geolocation",
+			body:           map[string]any{"stay": stay, "occupancies": occupancies},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid stay",
+			body: map[string]any{"stay": map[string]string{"checkIn": "2030-06-02", "checkOut": "2030-06-01"},
+				"occupancies": occupancies, "hotels": map[string][]int{"hotel": {1234}}},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.DefaultClient.Do(signedRequest(http.MethodPost, ts.URL+PathAvailability, searchRequest(tt.body), testSecret))
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus != http.StatusOK {
+				var e errorResponse
+				assert.NoError(t, json.NewDecoder(resp.Body).Decode(&e))
+				assert.NotEmpty(t, e.Error.Message)
+				return
+			}
+
+			var response availabilityResponse
+			assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+
+			var codes []int
+			boards := map[string]bool{}
+			for _, h := range response.Hotels.Hotels {
+				codes = append(codes, h.Code)
+				for _, r := range h.Rooms {
+					for _, rt := range r.Rates {
+						boards[rt.BoardCode] = true
+					}
+				}
+			}
+			assert.Equal(t, tt.expectedHotels, codes)
+
+			for _, b := range tt.expectedBoards {
+				assert.True(t, boards[b])
+			}
+			if tt.expectedBoards != nil {
+				assert.Len(t, boards, len(tt.expectedBoards))
+			}
+		})
+	}
+}
+
+func TestServer_Content(t *testing.T) {
+	_, ts := newTestServer()
+	defer ts.Close()
+
+	contentClient := client.NewHotelBedsContentClient(
+		config.HotelBedsConfig{
+			BaseURL:   ts.URL,
+			APIKey:    testAPIKey,
+			APISecret: testSecret,
+			Timeout:   config.Duration{Duration: time.Second * 5},
+		},
+		config.Default().Content,
+		nil,
+		nil,
+	)
+
+	tests := []struct {
+		name          string
+		codes         []int
+		from, to      int
+		expectedTotal int
+		expectedCodes []int
+	}{
+		{name: "First page", from: 1, to: 1, expectedTotal: 2, expectedCodes: []int{1234}},
+		{name: "Last page", from: 2, to: 10, expectedTotal: 2, expectedCodes: []int{5678}},
+		{name: "Past the end", from: 3, to: 10, expectedTotal: 2},
+		{name: "Selected hotels", codes: []int{5678, 9999}, from: 1, to: 10, expectedTotal: 1, expectedCodes: []int{5678}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := contentClient.Hotels(context.Background(), tt.codes, tt.from, tt.to)
+			assert.NoError(t, err)
+
+			var response dto.HotelbedsContentResponse
+			assert.NoError(t, json.Unmarshal(body, &response))
+			assert.Equal(t, tt.expectedTotal, response.Total)
+
+			var codes []int
+			for _, hotel := range response.Hotels {
+				codes = append(codes, hotel.Code)
+				assert.NotEmpty(t, hotel.Name.Content)
+				assert.NotEmpty(t, hotel.Images)
+				assert.Equal(t, map[int]int{1234: 4, 5678: 5}[hotel.Code], dto.StarsFromCategory(hotel.CategoryCode))
+			}
+			assert.Equal(t, tt.expectedCodes, codes)
+		})
+	}
+}
+
+func TestServer_Signature(t *testing.T) {
+	_, ts := newTestServer()
+	defer ts.Close()
+
+	resp, err := http.DefaultClient.Do(signedRequest(http.MethodGet, ts.URL+PathStatus, nil, "wrong-secret"))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	var e errorResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&e))
+	assert.Equal(t, "AUTHORIZATION_FAILED", e.Error.Code)
+
+	assert.NoError(t, newTestClient(ts.URL).Status(context.Background()))
+}
+
+func TestServer_CheckRateAndBooking(t *testing.T) {
+	_, ts := newTestServer()
+	defer ts.Close()
+
+	resp, err := http.DefaultClient.Do(signedRequest(http.MethodPost, ts.URL+PathAvailability, searchRequest(map[string]any{
+		"stay":        map[string]string{"checkIn": "2030-06-01", "checkOut": "2030-06-04"},
+		"occupancies": []map[string]int{{"rooms": 1, "adults": 2}},
+		"hotels":      map[string][]int{"hotel": {1234}},
+	}), testSecret))
+	assert.NoError(t, err)
+
+	var availability availabilityResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&availability))
+	resp.Body.Close()
+
+	quoted := availability.Hotels.Hotels[0].Rooms[0].Rates[0]
+	assert.Len(t, quoted.DailyRates, 3)
+
+	resp, err = http.DefaultClient.Do(signedRequest(http.MethodPost, ts.URL+PathCheckRates, searchRequest(checkRateRequest{
+		Rooms: []rateKeyRef{{RateKey: quoted.RateKey}},
+	}), testSecret))
+	assert.NoError(t, err)
+
+	var checked checkRateResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&checked))
+	resp.Body.Close()
+	assert.Equal(t, quoted.Net, checked.Hotel.Rooms[0].Rates[0].Net)
+	assert.Equal(t, quoted.Net, checked.Hotel.TotalNet)
+
+	booking := map[string]any{
+		"holder":          map[string]string{"name": "Jane", "surname": "Doe"},
+		"rooms":           []map[string]string{{"rateKey": quoted.RateKey}},
+		"clientReference": "ref-1",
+	}
+
+	resp, err = http.DefaultClient.Do(signedRequest(http.MethodPost, ts.URL+PathBookings, searchRequest(booking), testSecret))
+	assert.NoError(t, err)
+
+	var booked bookingResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&booked))
+	resp.Body.Close()
+	assert.Equal(t, "CONFIRMED", booked.Booking.Status)
+	assert.Equal(t, "1-0000001", booked.Booking.Reference)
+	assert.Equal(t, quoted.Net, booked.Booking.TotalNet)
+
+	resp, err = http.DefaultClient.Do(signedRequest(http.MethodPost, ts.URL+PathCheckRates, searchRequest(checkRateRequest{
+		Rooms: []rateKeyRef{{RateKey: "not-a-rate-key"}},
+	}), testSecret))
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestServer_Faults(t *testing.T) {
+	request := searchRequest(dto.HotelBedsSearchRequest{
+		Stay:        dto.Stay{CheckIn: "2030-06-01", CheckOut: "2030-06-02"},
+		Occupancies: []dto.Occupancy{{Rooms: 1, Adults: 2}},
+		Hotels:      &dto.HotelsFilter{Hotel: []int{1234}},
+	})
+
+	tests := []struct {
+		name        string
+		faults      []Fault
+		expectError bool
+		minDuration time.Duration
+	}{
+		{
+			name:        "Server error",
+			faults:      []Fault{{Status: http.StatusInternalServerError, Times: 1}},
+			expectError: true,
+		},
+		{
+			name:        "Rate limited",
+			faults:      []Fault{{Status: http.StatusTooManyRequests, Times: 1}},
+			expectError: true,
+		},
+		{
+			name:        "Latency",
+			faults:      []Fault{{Latency: Duration{50 * time.Millisecond}}},
+			minDuration: 50 * time.Millisecond,
+		},
+		{
+			name:   "Malformed body",
+			faults: []Fault{{Malformed: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ts := newTestServer(tt.faults...)
+			defer ts.Close()
+
+			start := time.Now()
+			response, err := newTestClient(ts.URL).SearchHotels(context.Background(), request)
+			assert.GreaterOrEqual(t, time.Since(start), tt.minDuration)
+
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			if tt.faults[0].Malformed {
+				assert.False(t, json.Valid(response))
+			} else {
+				assert.True(t, json.Valid(response))
+			}
+		})
+	}
+}
+
+func TestServer_FaultsAdmin(t *testing.T) {
+	fake, ts := newTestServer()
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, ts.URL+PathFaults, bytes.NewBufferString(`[{"status":503,"times":1},{"latency":"10ms"}]`))
+	resp, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	fault, ok := fake.faults.next()
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, fault.Status)
+
+	fault, ok = fake.faults.next()
+	assert.True(t, ok)
+	assert.Equal(t, 10*time.Millisecond, fault.Latency.Duration)
+
+	req, _ = http.NewRequest(http.MethodPut, ts.URL+PathFaults, bytes.NewBufferString(`{}`))
+	resp, err = http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,231 @@
+package fakehotelbeds
+
+// The types below mirror the subset of the Hotelbeds Booking and Content API schemas served by the fake.
+// They are kept separate from the dto package, which only decodes what liteAPI consumes.
+
+type availabilityRequest struct {
+	Stay        stay          `json:"stay"`
+	Occupancies []occupancy   `json:"occupancies"`
+	Hotels      *hotelsFilter `json:"hotels,omitempty"`
+	Destination *destination  `json:"destination,omitempty"`
+	Geolocation *geolocation  `json:"geolocation,omitempty"`
+	Boards      *boardsFilter `json:"boards,omitempty"`
+}
+
+type stay struct {
+	CheckIn  string `json:"checkIn"`
+	CheckOut string `json:"checkOut"`
+}
+
+type occupancy struct {
+	Rooms    int `json:"rooms"`
+	Adults   int `json:"adults"`
+	Children int `json:"children"`
+}
+
+type hotelsFilter struct {
+	Hotel []int `json:"hotel"`
+}
+
+type destination struct {
+	Code string `json:"code"`
+}
+
+type geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Radius    float64 `json:"radius"`
+	Unit      string  `json:"unit"`
+}
+
+type boardsFilter struct {
+	Board    []string `json:"board"`
+	Included bool     `json:"included"`
+}
+
+type availabilityResponse struct {
+	AuditData auditData   `json:"auditData"`
+	Hotels    hotelsBlock `json:"hotels"`
+}
+
+type auditData struct {
+	ProcessTime string `json:"processTime"`
+	Timestamp   string `json:"timestamp"`
+	Environment string `json:"environment"`
+}
+
+type hotelsBlock struct {
+	Hotels   []hotel `json:"hotels"`
+	CheckIn  string  `json:"checkIn"`
+	Total    int     `json:"total"`
+	CheckOut string  `json:"checkOut"`
+}
+
+type hotel struct {
+	Code            int    `json:"code"`
+	Name            string `json:"name"`
+	CategoryCode    string `json:"categoryCode"`
+	DestinationCode string `json:"destinationCode"`
+	Latitude        string `json:"latitude"`
+	Longitude       string `json:"longitude"`
+	Rooms           []room `json:"rooms"`
+	MinRate         string `json:"minRate"`
+	MaxRate         string `json:"maxRate"`
+	Currency        string `json:"currency"`
+	TotalNet        string `json:"totalNet,omitempty"`
+}
+
+type room struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Rates []rate `json:"rates"`
+}
+
+type rate struct {
+	RateKey              string               `json:"rateKey"`
+	RateClass            string               `json:"rateClass"`
+	RateType             string               `json:"rateType"`
+	Net                  string               `json:"net"`
+	Allotment            int                  `json:"allotment"`
+	PaymentType          string               `json:"paymentType"`
+	Packaging            bool                 `json:"packaging"`
+	BoardCode            string               `json:"boardCode"`
+	BoardName            string               `json:"boardName"`
+	CancellationPolicies []cancellationPolicy `json:"cancellationPolicies,omitempty"`
+	Taxes                *taxes               `json:"taxes,omitempty"`
+	Rooms                int                  `json:"rooms"`
+	Adults               int                  `json:"adults"`
+	Children             int                  `json:"children"`
+	DailyRates           []dailyRate          `json:"dailyRates,omitempty"`
+}
+
+type cancellationPolicy struct {
+	Amount string `json:"amount"`
+	From   string `json:"from"`
+}
+
+type taxes struct {
+	Taxes       []tax `json:"taxes"`
+	AllIncluded bool  `json:"allIncluded"`
+}
+
+type tax struct {
+	Included       bool   `json:"included"`
+	Amount         string `json:"amount"`
+	Currency       string `json:"currency"`
+	ClientAmount   string `json:"clientAmount,omitempty"`
+	ClientCurrency string `json:"clientCurrency,omitempty"`
+	Type           string `json:"type,omitempty"`
+}
+
+type dailyRate struct {
+	Offset           int    `json:"offset"`
+	DailyNet         string `json:"dailyNet"`
+	DailySellingRate string `json:"dailySellingRate,omitempty"`
+}
+
+type checkRateRequest struct {
+	Rooms []rateKeyRef `json:"rooms"`
+}
+
+type rateKeyRef struct {
+	RateKey string `json:"rateKey"`
+}
+
+type checkRateResponse struct {
+	AuditData auditData `json:"auditData"`
+	Hotel     hotel     `json:"hotel"`
+}
+
+type bookingRequest struct {
+	Holder          holder        `json:"holder"`
+	Rooms           []bookingRoom `json:"rooms"`
+	ClientReference string        `json:"clientReference"`
+}
+
+type holder struct {
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+}
+
+type bookingRoom struct {
+	RateKey string `json:"rateKey"`
+	Paxes   []pax  `json:"paxes"`
+}
+
+type pax struct {
+	RoomID  int    `json:"roomId"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Surname string `json:"surname"`
+}
+
+type bookingResponse struct {
+	AuditData auditData `json:"auditData"`
+	Booking   booking   `json:"booking"`
+}
+
+type booking struct {
+	Reference       string `json:"reference"`
+	ClientReference string `json:"clientReference"`
+	CreationDate    string `json:"creationDate"`
+	Status          string `json:"status"`
+	Holder          holder `json:"holder"`
+	Hotel           hotel  `json:"hotel"`
+	TotalNet        string `json:"totalNet"`
+	Currency        string `json:"currency"`
+}
+
+type errorResponse struct {
+	AuditData auditData   `json:"auditData"`
+	Error     errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type contentResponse struct {
+	AuditData auditData      `json:"auditData"`
+	From      int            `json:"from"`
+	To        int            `json:"to"`
+	Total     int            `json:"total"`
+	Hotels    []contentHotel `json:"hotels"`
+}
+
+type contentHotel struct {
+	Code            int               `json:"code"`
+	Name            contentText       `json:"name"`
+	Description     contentText       `json:"description"`
+	CountryCode     string            `json:"countryCode"`
+	DestinationCode string            `json:"destinationCode"`
+	CategoryCode    string            `json:"categoryCode"`
+	Coordinates     coordinates       `json:"coordinates"`
+	Address         contentText       `json:"address"`
+	City            contentText       `json:"city"`
+	Facilities      []contentFacility `json:"facilities"`
+	Images          []contentImage    `json:"images"`
+}
+
+type contentText struct {
+	Content string `json:"content"`
+}
+
+type coordinates struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+type contentFacility struct {
+	FacilityCode      int         `json:"facilityCode"`
+	FacilityGroupCode int         `json:"facilityGroupCode"`
+	Description       contentText `json:"description"`
+}
+
+type contentImage struct {
+	ImageTypeCode string `json:"imageTypeCode"`
+	Path          string `json:"path"`
+	Order         int    `json:"order"`
+	VisualOrder   int    `json:"visualOrder"`
+}
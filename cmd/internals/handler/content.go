@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+)
+
+type ContentHandler struct {
+	contentService service.ContentService
+}
+
+func NewContentHandler(contentService service.ContentService) *ContentHandler {
+	return &ContentHandler{
+		contentService: contentService,
+	}
+}
+
+// HotelContent serves the static content of the hotel whose liteAPI ID is in the :id path param
+func (h *ContentHandler) HotelContent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		content, err := h.contentService.HotelContent(c.Request.Context(), c.Param("id"))
+		if errors.Is(err, service.ErrContentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "hotel content failed", slog.String("error", err.Error()))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, content)
+	}
+}
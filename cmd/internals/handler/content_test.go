@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/handler/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupContentRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/hotels/:id/content", NewContentHandler(&mocks.MockContentService{}).HotelContent())
+	return router
+}
+
+func TestHotelContent(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		expectedCode  int
+		expectedName  string
+		expectedError string
+	}{
+		{
+			name:         "Found",
+			path:         "/hotels/1234/content",
+			expectedCode: http.StatusOK,
+			expectedName: "Zafiro Palace",
+		},
+		{
+			name:          "Not found",
+			path:          "/hotels/4321/content",
+			expectedCode:  http.StatusNotFound,
+			expectedError: "hotel content not found: 4321",
+		},
+		{
+			name:          "Service error",
+			path:          "/hotels/9999/content",
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: "service error",
+		},
+	}
+
+	router := setupContentRouter()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var content dto.HotelContent
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &content))
+			assert.Equal(t, tt.expectedName, content.Name)
+			assert.Equal(t, 5, content.Stars)
+			assert.Len(t, content.Images, 1)
+		})
+	}
+}
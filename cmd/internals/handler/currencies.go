@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+)
+
+// maxGapReportDays caps the range of a rate gap report
+const maxGapReportDays = 3660
+
+type CurrenciesHandler struct {
+	currencyService service.CurrencyService
+}
+
+func NewCurrenciesHandler(currencyService service.CurrencyService) *CurrenciesHandler {
+	return &CurrenciesHandler{
+		currencyService: currencyService,
+	}
+}
+
+// List serves the supported currencies with their decimals and symbols
+func (h *CurrenciesHandler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, dto.CurrencyListResponse{Data: h.currencyService.Currencies()})
+	}
+}
+
+// Convert converts amount from one currency to another at the plain rate, without the FX spread of hotel
+// prices: the latest historical rate of the pair, like hotel prices, or the one valid on at when given
+func (h *CurrenciesHandler) Convert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query dto.CurrencyConvertQueryParams
+		if err := c.ShouldBindQuery(&query); err != nil {
+			h.badRequest(c, errors.New("amount, from and to are required and amount must be a number"))
+			return
+		}
+		if *query.Amount < 0 || math.IsNaN(*query.Amount) || math.IsInf(*query.Amount, 0) {
+			h.badRequest(c, errors.New("amount must not be negative"))
+			return
+		}
+
+		from, to := strings.ToUpper(query.From), strings.ToUpper(query.To)
+
+		var conversion dto.Conversion
+		if query.At != "" {
+			at, err := time.Parse("2006-01-02", query.At)
+			if err != nil {
+				h.badRequest(c, errors.New("at must be in format YYYY-MM-DD"))
+				return
+			}
+
+			conversion, err = h.currencyService.ConvertAt(*query.Amount, from, to, at)
+			if err != nil {
+				h.conversionError(c, err)
+				return
+			}
+		} else {
+			var err error
+			conversion, err = h.currencyService.ConvertCurrent(*query.Amount, from, to)
+			if err != nil {
+				h.conversionError(c, err)
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, dto.ConversionResponse{
+			Amount:    *query.Amount,
+			From:      from,
+			To:        to,
+			Converted: conversion.Amount,
+			Rate:      conversion.Rate,
+			RatesAsOf: ratesAsOf(conversion.AsOf),
+		})
+	}
+}
+
+// Rates serves the rate table for the base currency and when it was last refreshed
+func (h *CurrenciesHandler) Rates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		base := strings.ToUpper(c.Query("base"))
+		if base == "" {
+			h.badRequest(c, errors.New("base is required"))
+			return
+		}
+
+		rates, err := h.currencyService.Rates(base)
+		if err != nil {
+			h.conversionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.RateTableResponse{
+			Base:      base,
+			RatesAsOf: ratesAsOf(h.currencyService.RatesAsOf()),
+			Rates:     rates,
+		})
+	}
+}
+
+// RateGaps serves the days between from and to without historical rates
+func (h *CurrenciesHandler) RateGaps() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		from, errFrom := time.Parse("2006-01-02", c.Query("from"))
+		to, errTo := time.Parse("2006-01-02", c.Query("to"))
+		if errFrom != nil || errTo != nil {
+			h.badRequest(c, errors.New("from and to are required in format YYYY-MM-DD"))
+			return
+		}
+
+		if to.Before(from) {
+			h.badRequest(c, errors.New("to must not be before from"))
+			return
+		}
+
+		if to.Sub(from) > maxGapReportDays*24*time.Hour {
+			h.badRequest(c, fmt.Errorf("a gap report covers at most %d days", maxGapReportDays))
+			return
+		}
+
+		c.JSON(http.StatusOK, h.currencyService.RateGaps(from, to))
+	}
+}
+
+func (h *CurrenciesHandler) badRequest(c *gin.Context, err error) {
+	slog.WarnContext(c.Request.Context(), "invalid currency request", slog.String("error", err.Error()))
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// conversionError answers unknown currencies with 400, pairs or dates without a historical rate with 404
+// and anything else with 500
+func (h *CurrenciesHandler) conversionError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrUnknownCurrency) {
+		h.badRequest(c, err)
+		return
+	}
+
+	if errors.Is(err, service.ErrRateNotFound) {
+		slog.WarnContext(c.Request.Context(), "no exchange rate", slog.String("error", err.Error()))
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	slog.ErrorContext(c.Request.Context(), "currency conversion failed", slog.String("error", err.Error()))
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// ratesAsOf formats when the rates were refreshed; the static rate table is never refreshed and has no date
+func ratesAsOf(asOf time.Time) string {
+	if asOf.IsZero() {
+		return "static"
+	}
+
+	return asOf.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	servicemocks "github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCurrenciesRouter(currencyService service.CurrencyService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	currenciesHandler := NewCurrenciesHandler(currencyService)
+	router.GET("/currencies", currenciesHandler.List())
+	router.GET("/currencies/convert", currenciesHandler.Convert())
+	router.GET("/currencies/rates", currenciesHandler.Rates())
+	router.GET("/currencies/rates/gaps", currenciesHandler.RateGaps())
+	return router
+}
+
+func TestListCurrencies(t *testing.T) {
+	router := setupCurrenciesRouter(service.NewCurrencyService(config.CurrencyConfig{}, nil))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/currencies", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response dto.CurrencyListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEmpty(t, response.Data)
+	assert.Contains(t, response.Data, dto.Currency{Code: "USD", NumericCode: "840", Decimals: 2, Symbol: "$"})
+}
+
+func TestConvertCurrency(t *testing.T) {
+	history := service.NewRateStore()
+	_, err := history.Load(strings.NewReader("date,base,currency,rate\n2024-01-10,USD,EUR,0.9\n"))
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name              string
+		currencyService   service.CurrencyService
+		query             string
+		expectedCode      int
+		expectedConverted float64
+		expectedRate      float64
+		expectedTo        string
+		expectedRatesAsOf string
+		expectedError     string
+	}{
+		{
+			name:              "Valid conversion",
+			currencyService:   service.NewCurrencyService(config.CurrencyConfig{}, nil),
+			query:             "amount=100.5&from=USD&to=eur",
+			expectedCode:      http.StatusOK,
+			expectedConverted: 100.5,
+			expectedRate:      1,
+			expectedTo:        "EUR",
+		},
+		{
+			name:              "Rounded half-even to target decimals",
+			currencyService:   service.NewCurrencyService(config.CurrencyConfig{Rounding: config.RoundingHalfEven}, nil),
+			query:             "amount=12.5&from=USD&to=JPY",
+			expectedCode:      http.StatusOK,
+			expectedConverted: 12,
+			expectedRate:      1,
+			expectedTo:        "JPY",
+		},
+		{
+			name:              "Rounded like hotel prices despite float noise",
+			currencyService:   service.NewCurrencyService(config.CurrencyConfig{Rounding: config.RoundingHalfEven}, nil),
+			query:             "amount=1.015&from=USD&to=EUR",
+			expectedCode:      http.StatusOK,
+			expectedConverted: 1.02,
+			expectedRate:      1,
+			expectedTo:        "EUR",
+		},
+		{
+			name:              "Historical rate",
+			currencyService:   &servicemocks.MockCurrencyService{Rate: 0.9},
+			query:             "amount=100&from=USD&to=EUR&at=2024-01-15",
+			expectedCode:      http.StatusOK,
+			expectedConverted: 90,
+			expectedRate:      0.9,
+			expectedTo:        "EUR",
+			expectedRatesAsOf: "2024-01-15T00:00:00Z",
+		},
+		{
+			name:            "Invalid historical date",
+			currencyService: service.NewCurrencyService(config.CurrencyConfig{}, nil),
+			query:           "amount=100&from=USD&to=EUR&at=15-01-2024",
+			expectedCode:    http.StatusBadRequest,
+			expectedError:   "at must be in format YYYY-MM-DD",
+		},
+		{
+			name:            "Before the first historical rate",
+			currencyService: service.NewCurrencyService(config.CurrencyConfig{}, history),
+			query:           "amount=100&from=USD&to=EUR&at=2024-01-01",
+			expectedCode:    http.StatusNotFound,
+			expectedError:   "no exchange rate from USD to EUR on or before 2024-01-01",
+		},
+		{
+			name:            "Missing amount",
+			currencyService: service.NewCurrencyService(config.CurrencyConfig{}, nil),
+			query:           "from=USD&to=EUR",
+			expectedCode:    http.StatusBadRequest,
+			expectedError:   "amount, from and to are required and amount must be a number",
+		},
+		{
+			name:            "Negative amount",
+			currencyService: service.NewCurrencyService(config.CurrencyConfig{}, nil),
+			query:           "amount=-1&from=USD&to=EUR",
+			expectedCode:    http.StatusBadRequest,
+			expectedError:   "amount must not be negative",
+		},
+		{
+			name:            "Unknown currency",
+			currencyService: service.NewCurrencyService(config.CurrencyConfig{}, nil),
+			query:           "amount=1&from=USD&to=ABC",
+			expectedCode:    http.StatusBadRequest,
+			expectedError:   "failed to find Currency by code: ABC",
+		},
+		{
+			name:            "Service error",
+			currencyService: &servicemocks.MockCurrencyService{ShouldError: true},
+			query:           "amount=1&from=USD&to=EUR",
+			expectedCode:    http.StatusInternalServerError,
+			expectedError:   "Conversion error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupCurrenciesRouter(tt.currencyService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/currencies/convert?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var response dto.ConversionResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedConverted, response.Converted)
+			assert.Equal(t, tt.expectedRate, response.Rate)
+			assert.Equal(t, tt.expectedTo, response.To)
+			assert.NotEmpty(t, response.RatesAsOf)
+			if tt.expectedRatesAsOf != "" {
+				assert.Equal(t, tt.expectedRatesAsOf, response.RatesAsOf)
+			}
+		})
+	}
+}
+
+func TestCurrencyRates(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			name:         "Known base",
+			query:        "base=eur",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:          "Missing base",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "base is required",
+		},
+		{
+			name:          "Unknown base",
+			query:         "base=ABC",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "failed to find Currency by code: ABC",
+		},
+	}
+
+	router := setupCurrenciesRouter(service.NewCurrencyService(config.CurrencyConfig{}, nil))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/currencies/rates?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var response dto.RateTableResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "EUR", response.Base)
+			assert.Equal(t, 1.0, response.Rates["USD"])
+			assert.NotEmpty(t, response.RatesAsOf)
+		})
+	}
+}
+
+func TestCurrencyRateGaps(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		expectedCode  int
+		expectedDays  int
+		expectedError string
+	}{
+		{
+			name:         "Valid range",
+			query:        "from=2024-01-01&to=2024-01-31",
+			expectedCode: http.StatusOK,
+			expectedDays: 31,
+		},
+		{
+			name:          "Missing dates",
+			query:         "from=2024-01-01",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "from and to are required in format YYYY-MM-DD",
+		},
+		{
+			name:          "Reversed range",
+			query:         "from=2024-01-31&to=2024-01-01",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "to must not be before from",
+		},
+		{
+			name:          "Range too long",
+			query:         "from=2000-01-01&to=2024-01-01",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "a gap report covers at most 3660 days",
+		},
+	}
+
+	router := setupCurrenciesRouter(service.NewCurrencyService(config.CurrencyConfig{}, nil))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/currencies/rates/gaps?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var response dto.RateGapReport
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedDays, response.Days)
+			assert.Empty(t, response.MissingDays)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// fixturesDir holds the supplier exchanges captured by the recording client
+const fixturesDir = "../client/testdata/fixtures"
+
+func assertGolden(t *testing.T, name string, actual []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		var indented bytes.Buffer
+		assert.NoError(t, json.Indent(&indented, actual, "", "  "))
+		assert.NoError(t, os.MkdirAll("testdata", 0o755))
+		assert.NoError(t, os.WriteFile(path, append(indented.Bytes(), '\n'), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestSearchHotels_Golden(t *testing.T) {
+	replayer := client.NewRecordingClient(nil, config.RecorderConfig{Mode: config.RecorderModeReplay, Dir: fixturesDir})
+
+	hotelService := service.NewHotelService(replayer, service.NewCurrencyService(config.Default().Currency, nil), nil, "")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/hotels", NewHotelsHandler(hotelService, service.NewFlexibleSearchService(hotelService, config.Default().Search), service.NewSearchPaginator(config.Default().Search)).SearchHotels())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/hotels?hotelIds=1234,5678&checkin=2030-06-01&checkout=2030-06-03&occupancies=[{\"rooms\":1,\"adults\":2}]&currency=EUR", nil)
+	req.Header.Set(util.HeaderSupplierConfig, "test-supplier-config")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertGolden(t, "search_hotels.golden.json", w.Body.Bytes())
+}
@@ -2,14 +2,20 @@ package handler
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/health"
 )
 
-type HealthHandler struct{}
+type HealthHandler struct {
+	checker *health.Checker
+}
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
 }
 
 func (h *HealthHandler) Handle() func(c *gin.Context) {
@@ -19,3 +25,28 @@ func (h *HealthHandler) Handle() func(c *gin.Context) {
 		})
 	}
 }
+
+// Live reports that the process is running; it never checks dependencies
+func (h *HealthHandler) Live() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"status": health.StatusUp,
+		})
+	}
+}
+
+// Ready reports per-component readiness and returns 503 when any component is down.
+// Pass deep=true to also ping the supplier; that result is cached by the checker.
+func (h *HealthHandler) Ready() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		deep, _ := strconv.ParseBool(c.Query("deep"))
+		report := h.checker.Run(c.Request.Context(), deep)
+
+		status := http.StatusOK
+		if report.Status != health.StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.JSON(status, report)
+	}
+}
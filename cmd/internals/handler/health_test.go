@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/health"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupHealthRouter(checker *health.Checker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	healthHandler := NewHealthHandler(checker)
+	router.GET("/health/live", healthHandler.Live())
+	router.GET("/health/ready", healthHandler.Ready())
+	return router
+}
+
+func TestHealthReady(t *testing.T) {
+	up := health.NewCheck("config", func(context.Context) error { return nil })
+	down := health.NewCheck("hotelbeds", func(context.Context) error { return errors.New("unreachable") })
+
+	tests := []struct {
+		name           string
+		path           string
+		checker        *health.Checker
+		expectedCode   int
+		expectedStatus string
+	}{
+		{
+			name:           "Live",
+			path:           "/health/live",
+			checker:        health.NewChecker([]health.Check{down}, nil),
+			expectedCode:   http.StatusOK,
+			expectedStatus: health.StatusUp,
+		},
+		{
+			name:           "Ready",
+			path:           "/health/ready",
+			checker:        health.NewChecker([]health.Check{up}, []health.Check{down}),
+			expectedCode:   http.StatusOK,
+			expectedStatus: health.StatusUp,
+		},
+		{
+			name:           "Not ready",
+			path:           "/health/ready",
+			checker:        health.NewChecker([]health.Check{up, down}, nil),
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: health.StatusDown,
+		},
+		{
+			name:           "Deep check failing",
+			path:           "/health/ready?deep=true",
+			checker:        health.NewChecker([]health.Check{up}, []health.Check{down}),
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedStatus: health.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupHealthRouter(tt.checker)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			var response health.Report
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedStatus, response.Status)
+		})
+	}
+}
@@ -2,29 +2,55 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
 )
 
-type HotelsHandler struct {
-	hotelService service.HotelService
-}
+const (
+	// maxRadiusKm is the largest geolocation search Hotelbeds accepts
+	maxRadiusKm     = 200
+	kilometersPerMi = 1.609344
+)
 
-func NewHotelsHandler() *HotelsHandler {
-	return &HotelsHandler{
-		hotelService: service.NewHotelService(),
-	}
+// hotelsShaper shapes hotel prices; full, with the supplier echo, stays the default for existing clients
+var hotelsShaper = NewResponseShaper("data", map[string]ResponseProfile{
+	ProfileMinimal:  {Include: []string{"data.hotelId", "data.currency", "data.price", "nextCursor", "warnings"}},
+	ProfileStandard: {Exclude: []string{"supplier"}},
+	ProfileFull:     {},
+}, ProfileFull)
+
+// calendarsShaper shapes flexible-date price calendars, which carry no supplier echo
+var calendarsShaper = NewResponseShaper("data", map[string]ResponseProfile{
+	ProfileMinimal:  {Include: []string{"data.hotelId", "data.currency", "data.cheapest", "failedCheckIns", "warnings"}},
+	ProfileStandard: {},
+	ProfileFull:     {},
+}, ProfileFull)
+
+type HotelsHandler struct {
+	hotelService    service.HotelService
+	flexibleService service.FlexibleSearchService
+	paginator       service.SearchPaginator
 }
 
-func NewHotelsHandlerWithService(service service.HotelService) *HotelsHandler {
+func NewHotelsHandler(service service.HotelService, flexibleService service.FlexibleSearchService, paginator service.SearchPaginator) *HotelsHandler {
 	return &HotelsHandler{
-		hotelService: service,
+		hotelService:    service,
+		flexibleService: flexibleService,
+		paginator:       paginator,
 	}
 }
 
@@ -33,134 +59,395 @@ func (h *HotelsHandler) SearchHotels() gin.HandlerFunc {
 }
 
 func (h *HotelsHandler) handle(c *gin.Context) {
-	var query dto.HotelSearchQueryParams
-	if err := c.ShouldBindQuery(&query); err != nil {
+	shaper := hotelsShaper
+	if flexible, _ := strconv.ParseBool(c.Query("flexible")); flexible {
+		shaper = calendarsShaper
+	}
+
+	shape, err := shaper.Parse(c)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "invalid hotel search request", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
-	// Validate date format and values
-	checkIn, err := time.Parse("2006-01-02", query.CheckIn)
+	limit, err := pageLimit(c)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "check-in date must be in format YYYY-MM-DD",
-		})
+		h.pageError(c, err)
 		return
 	}
 
-	checkOut, err := time.Parse("2006-01-02", query.CheckOut)
+	// A cursor replaces the search params: the page is served from the stored search
+	if cursor := c.Query("cursor"); cursor != "" {
+		h.handleNextPage(c, shape, cursor, limit)
+		return
+	}
+
+	serviceParams, err := h.validate(c)
 	if err != nil {
+		slog.WarnContext(c.Request.Context(), "invalid hotel search request", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "check-out date must be in format YYYY-MM-DD",
+			"error": err.Error(),
 		})
 		return
 	}
 
-	// Check if dates are in the future
-	now := time.Now().Truncate(24 * time.Hour)
-	if checkIn.Before(now) {
+	// In flexible mode the validated dates are the window the stays must fit in
+	flexibleParams, flexible, err := h.validateFlexible(c, serviceParams)
+	if err != nil {
+		slog.WarnContext(c.Request.Context(), "invalid hotel search request", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "check-in date must be in the future",
+			"error": err.Error(),
 		})
 		return
 	}
 
-	if checkOut.Before(now) {
+	if flexible {
+		h.handleFlexible(c, shape, flexibleParams)
+		return
+	}
+
+	serviceResponse, err := h.hotelService.SearchHotels(c.Request.Context(), serviceParams)
+	if err != nil {
+		searchError(c, "hotel search failed", err)
+		return
+	}
+
+	response := dto.HotelPriceResponse{
+		Data: serviceResponse.HotelPrices,
+		Supplier: dto.Supplier{
+			Request:  serviceResponse.SupplierRequest,
+			Response: serviceResponse.SupplierResponse,
+		},
+		Warnings: serviceResponse.Warnings,
+	}
+
+	if limit != 0 {
+		response, err = h.paginator.FirstPage(c.Request.Context(), response, limit)
+		if err != nil {
+			h.pageError(c, err)
+			return
+		}
+	}
+
+	// return response
+	respond(c, shape, response)
+}
+
+func (h *HotelsHandler) handleNextPage(c *gin.Context, shape ResponseShape, cursor string, limit int) {
+	response, err := h.paginator.NextPage(c.Request.Context(), cursor, limit)
+	if err != nil {
+		h.pageError(c, err)
+		return
+	}
+
+	respond(c, shape, response)
+}
+
+// searchError answers a spent supplier quota with 503 and a Retry-After until the quota resets, and anything
+// else with 500
+func searchError(c *gin.Context, message string, err error) {
+	slog.ErrorContext(c.Request.Context(), message, slog.String("error", err.Error()))
+
+	status := http.StatusInternalServerError
+	if errors.Is(err, client.ErrSupplierQuotaExceeded) {
+		status = http.StatusServiceUnavailable
+		c.Header(util.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(client.QuotaResetsIn(time.Now()).Seconds()))))
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// pageLimit reads the page size of a search or of its next page; 0 leaves the search unpaged, or keeps
+// the page size of the cursor
+func pageLimit(c *gin.Context) (int, error) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "0"))
+	if err != nil {
+		return 0, fmt.Errorf("%w: limit must be a number", service.ErrInvalidPage)
+	}
+
+	return limit, nil
+}
+
+// pageError answers invalid limits and cursors with 400 and anything else with 500
+func (h *HotelsHandler) pageError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, service.ErrInvalidPage) {
+		status = http.StatusBadRequest
+		slog.WarnContext(c.Request.Context(), "invalid hotel search page", slog.String("error", err.Error()))
+	} else {
+		slog.ErrorContext(c.Request.Context(), "hotel search paging failed", slog.String("error", err.Error()))
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
+
+func (h *HotelsHandler) handleFlexible(c *gin.Context, shape ResponseShape, params dto.FlexibleSearchServiceParams) {
+	serviceResponse, err := h.flexibleService.SearchFlexible(c.Request.Context(), params)
+	if errors.Is(err, service.ErrInvalidFlexibleSearch) {
+		slog.WarnContext(c.Request.Context(), "invalid hotel search request", slog.String("error", err.Error()))
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "check-out date must be in the future",
+			"error": err.Error(),
 		})
 		return
 	}
+	if err != nil {
+		searchError(c, "flexible hotel search failed", err)
+		return
+	}
+
+	respond(c, shape, dto.FlexiblePriceResponse{
+		Data:           serviceResponse.Calendars,
+		FailedCheckIns: serviceResponse.FailedCheckIns,
+		Warnings:       serviceResponse.Warnings,
+	})
+}
+
+// validateFlexible reports whether a flexible search was requested and, if so, builds its params
+// from the already validated search params
+func (h *HotelsHandler) validateFlexible(c *gin.Context, serviceParams dto.HotelSearchServiceParams) (params dto.FlexibleSearchServiceParams, ok bool, err error) {
+	var query dto.HotelSearchQueryParams
+	if err = c.ShouldBindQuery(&query); err != nil {
+		return params, false, err
+	}
+
+	if !query.Flexible {
+		return params, false, nil
+	}
+
+	if query.Nights < 1 {
+		return params, true, errors.New("nights must be at least 1 for a flexible search")
+	}
+
+	// Price calendars are not paginated
+	if c.Query("limit") != "" {
+		return params, true, errors.New("limit is not supported for a flexible search")
+	}
+
+	params = dto.FlexibleSearchServiceParams{
+		WindowStart: serviceParams.CheckIn,
+		WindowEnd:   serviceParams.CheckOut,
+		Nights:      query.Nights,
+		HotelIDs:    serviceParams.HotelIDs,
+		Destination: serviceParams.Destination,
+		Geolocation: serviceParams.Geolocation,
+		Currency:    serviceParams.Currency,
+		Occupancies: serviceParams.Occupancies,
+		Filters:     serviceParams.Filters,
+	}
+
+	return params, true, nil
+}
+
+// validate parses and validates the query params and headers into the service params
+func (h *HotelsHandler) validate(c *gin.Context) (serviceParams dto.HotelSearchServiceParams, err error) {
+	_, span := telemetry.StartSpan(c.Request.Context(), "HotelsHandler.validate")
+	defer func() {
+		if err != nil {
+			telemetry.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	var query dto.HotelSearchQueryParams
+	if err = c.ShouldBindQuery(&query); err != nil {
+		return serviceParams, err
+	}
+
+	return validateSearch(c, query)
+}
+
+// validateSearch validates the search params, wherever they were bound from, into the service params
+func validateSearch(c *gin.Context, query dto.HotelSearchQueryParams) (serviceParams dto.HotelSearchServiceParams, err error) {
+	// Validate date format and values
+	checkIn, err := time.Parse("2006-01-02", query.CheckIn)
+	if err != nil {
+		return serviceParams, errors.New("check-in date must be in format YYYY-MM-DD")
+	}
+
+	checkOut, err := time.Parse("2006-01-02", query.CheckOut)
+	if err != nil {
+		return serviceParams, errors.New("check-out date must be in format YYYY-MM-DD")
+	}
+
+	// Check if dates are in the future
+	now := time.Now().Truncate(24 * time.Hour)
+	if checkIn.Before(now) {
+		return serviceParams, errors.New("check-in date must be in the future")
+	}
+
+	if checkOut.Before(now) {
+		return serviceParams, errors.New("check-out date must be in the future")
+	}
 
 	// Check if check-out is after check-in
 	if checkOut.Before(checkIn) || checkOut.Equal(checkIn) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "check-out date must be after check-in date",
-		})
-		return
+		return serviceParams, errors.New("check-out date must be after check-in date")
 	}
 
-	// Get the supplier config from header
-	supplierConfig := c.GetHeader("x-liteapi-supplier-config")
+	// Get the supplier config from header, falling back to the tenant's default
+	supplierConfig := c.GetHeader(util.HeaderSupplierConfig)
+	if tenant, ok := auth.TenantFromContext(c.Request.Context()); ok && supplierConfig == "" {
+		supplierConfig = tenant.SupplierConfig
+	}
 	if supplierConfig == "" {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": "supplier config is required",
-			},
-		)
-		return
+		return serviceParams, errors.New("supplier config is required")
 	}
 
-	hotelIds := []int{}
 	occupancies := []dto.Occupancy{}
 
-	// Parse hotel IDs
-	for _, id := range strings.Split(query.HotelIds, ",") {
-		i, err := strconv.Atoi(id)
-		if err != nil {
-			c.JSON(
-				http.StatusBadRequest,
-				gin.H{
-					"error": "invalid hotel ID format",
-				},
-			)
-			return
-		}
-		hotelIds = append(hotelIds, i)
+	hotelIds, geolocation, err := validateSearchMode(query)
+	if err != nil {
+		return serviceParams, err
 	}
 
 	// Parse occupancies from query params
-	if err := json.Unmarshal([]byte(query.Occupancies), &occupancies); err != nil {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": "invalid occupancies format",
-			},
-		)
-		return
+	if err = json.Unmarshal([]byte(query.Occupancies), &occupancies); err != nil {
+		return serviceParams, errors.New("invalid occupancies format")
 	}
 
 	// Validate currency
 	if query.Currency == "" {
-		c.JSON(
-			http.StatusBadRequest,
-			gin.H{
-				"error": "currency is required",
-			},
-		)
-		return
+		return serviceParams, errors.New("currency is required")
 	}
 
-	serviceParams := dto.HotelSearchServiceParams{
+	filters, err := validateFilters(query)
+	if err != nil {
+		return serviceParams, err
+	}
+
+	sortOrder, err := dto.ParseSortOrder(query.Sort)
+	if err != nil {
+		return serviceParams, err
+	}
+
+	serviceParams = dto.HotelSearchServiceParams{
 		CheckIn:     query.CheckIn,
 		CheckOut:    query.CheckOut,
-		Currency:    query.Currency,
+		Currency:    strings.ToUpper(query.Currency),
 		HotelIDs:    hotelIds,
+		Destination: strings.ToUpper(query.Destination),
+		Geolocation: geolocation,
 		Occupancies: occupancies,
+		Filters:     filters,
+		Sort:        sortOrder,
 	}
 
-	serviceResponse, err := h.hotelService.SearchHotels(serviceParams)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
-		return
+	return serviceParams, nil
+}
+
+// validateSearchMode checks that the hotels are selected by exactly one of a hotel list, a destination code
+// or a geolocation circle, and parses the hotel list or circle
+func validateSearchMode(query dto.HotelSearchQueryParams) (hotelIds []string, geolocation *dto.Geolocation, err error) {
+	geo := query.Latitude != nil || query.Longitude != nil || query.Radius != 0 || query.Unit != ""
+
+	modes := 0
+	for _, selected := range []bool{query.HotelIds != "", query.Destination != "", geo} {
+		if selected {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return nil, nil, errors.New("exactly one of hotelIds, destination or latitude/longitude is required")
 	}
 
-	response := dto.HotelPriceResponse{
-		Data: serviceResponse.HotelPrices,
-		Supplier: dto.Supplier{
-			Request:  serviceResponse.SupplierRequest,
-			Response: serviceResponse.SupplierResponse,
-		},
+	switch {
+	case query.HotelIds != "":
+		// liteAPI hotel IDs are opaque; the service maps them to supplier codes
+		for _, id := range strings.Split(query.HotelIds, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				return nil, nil, errors.New("invalid hotel ID format")
+			}
+			hotelIds = append(hotelIds, id)
+		}
+	case geo:
+		geolocation, err = validateGeolocation(query)
 	}
 
-	// return response
-	c.JSON(
-		http.StatusOK,
-		response,
-	)
+	return hotelIds, geolocation, err
+}
+
+// validateGeolocation checks the coordinates and the radius, which is capped at maxRadiusKm
+func validateGeolocation(query dto.HotelSearchQueryParams) (*dto.Geolocation, error) {
+	if query.Latitude == nil || query.Longitude == nil {
+		return nil, errors.New("latitude and longitude are both required")
+	}
+
+	if *query.Latitude < -90 || *query.Latitude > 90 {
+		return nil, errors.New("latitude must be between -90 and 90")
+	}
+
+	if *query.Longitude < -180 || *query.Longitude > 180 {
+		return nil, errors.New("longitude must be between -180 and 180")
+	}
+
+	unit := strings.ToLower(query.Unit)
+	radiusKm := query.Radius
+	switch unit {
+	case "":
+		unit = dto.UnitKilometers
+	case dto.UnitKilometers:
+	case dto.UnitMiles:
+		radiusKm *= kilometersPerMi
+	default:
+		return nil, errors.New("unit must be km or mi")
+	}
+
+	if query.Radius <= 0 || radiusKm > maxRadiusKm {
+		return nil, fmt.Errorf("radius must be greater than 0 and at most %v km", maxRadiusKm)
+	}
+
+	return &dto.Geolocation{
+		Latitude:  *query.Latitude,
+		Longitude: *query.Longitude,
+		Radius:    query.Radius,
+		Unit:      unit,
+	}, nil
+}
+
+// validateFilters parses and validates the rate filters of the query params
+func validateFilters(query dto.HotelSearchQueryParams) (filters dto.RateFilters, err error) {
+	filters = dto.RateFilters{
+		Refundable:  query.Refundable,
+		MinPrice:    query.MinPrice,
+		MaxPrice:    query.MaxPrice,
+		PaymentType: strings.ToUpper(query.PaymentType),
+		Packaging:   query.Packaging,
+		RateClass:   strings.ToUpper(query.RateClass),
+	}
+
+	if query.Boards != "" {
+		if filters.Boards, err = dto.ParseBoards(query.Boards); err != nil {
+			return filters, err
+		}
+	}
+
+	if filters.MinPrice < 0 || filters.MaxPrice < 0 {
+		return filters, errors.New("price bounds must not be negative")
+	}
+
+	if filters.MaxPrice > 0 && filters.MinPrice > filters.MaxPrice {
+		return filters, errors.New("minPrice must not be greater than maxPrice")
+	}
+
+	switch filters.PaymentType {
+	case "", dto.PaymentTypeAtWeb, dto.PaymentTypeAtHotel:
+	default:
+		return filters, errors.New("paymentType must be AT_WEB or AT_HOTEL")
+	}
+
+	switch filters.RateClass {
+	case "", dto.RateClassRefundable, dto.RateClassNonRefundable:
+	default:
+		return filters, errors.New("rateClass must be NOR or NRF")
+	}
+
+	return filters, nil
 }
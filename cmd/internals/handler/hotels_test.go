@@ -5,19 +5,28 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/handler/mocks"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	telemetrymocks "github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry/mocks"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func setupRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	mockService := &mocks.MockHotelService{}
-	hotelsHandler := NewHotelsHandlerWithService(mockService)
+	hotelsHandler := NewHotelsHandler(mockService, &mocks.MockFlexibleSearchService{}, service.NewSearchPaginator(config.Default().Search))
 	router.GET("/hotels/search", hotelsHandler.SearchHotels())
 	return router
 }
@@ -34,8 +43,9 @@ func TestSearchHotels(t *testing.T) {
 	invalidCheckin := fmt.Sprintf("hotelIds=1234,5678&checkin=asdf&checkout=%s&occupancies=[{\"adults\":2,\"children\":1,\"childrenAges\":[10]}]&currency=EUR", checkoutDate)
 	invalidCheckout := fmt.Sprintf("hotelIds=1234,5678&checkin=%s&checkout=2024&occupancies=[{\"adults\":2,\"children\":1,\"childrenAges\":[10]}]&currency=EUR", checkinDate)
 	checkoutBeforeCheckin := fmt.Sprintf("hotelIds=1234,5678&checkin=%s&checkout=%s&occupancies=[{\"adults\":2,\"children\":1,\"childrenAges\":[10]}]&currency=EUR", checkinDate, badCheckoutDate)
-	invalidHotelID := fmt.Sprintf("hotelIds=asdf,5678&checkin=%s&checkout=%s&occupancies=[{\"adults\":2,\"children\":1,\"childrenAges\":[10]}]&currency=EUR", checkinDate, checkoutDate)
+	invalidHotelID := fmt.Sprintf("hotelIds=1234,,5678&checkin=%s&checkout=%s&occupancies=[{\"adults\":2,\"children\":1,\"childrenAges\":[10]}]&currency=EUR", checkinDate, checkoutDate)
 	invalidOccupancies := fmt.Sprintf("hotelIds=1234,5678&checkin=%s&checkout=%s&occupancies=[{\":[10]}]&currency=EUR", checkinDate, checkoutDate)
+	invalidBoards := validParams + "&boards=BED_AND_BREAKFAST,BUFFET"
 	downstreamErr := fmt.Sprintf("hotelIds=9999,5678&checkin=%s&checkout=%s&occupancies=[{\"adults\":2,\"children\":1,\"childrenAges\":[10]}]&currency=EUR", checkinDate, checkoutDate)
 
 	tests := []struct {
@@ -100,6 +110,160 @@ func TestSearchHotels(t *testing.T) {
 			expectedCode:   http.StatusBadRequest,
 			expectedError:  "invalid occupancies format",
 		},
+		{
+			name:           "Valid boards",
+			queryParams:    validParams + "&boards=bed_and_breakfast,HALF_BOARD",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:           "Unknown board",
+			queryParams:    invalidBoards,
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "unknown board: BUFFET",
+		},
+		{
+			name:           "Valid rate filters",
+			queryParams:    validParams + "&minPrice=100&maxPrice=300&paymentType=at_web&packaging=false&rateClass=NOR",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:           "Negative price bound",
+			queryParams:    validParams + "&minPrice=-1",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "price bounds must not be negative",
+		},
+		{
+			name:           "Inverted price bounds",
+			queryParams:    validParams + "&minPrice=300&maxPrice=100",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "minPrice must not be greater than maxPrice",
+		},
+		{
+			name:           "Unknown payment type",
+			queryParams:    validParams + "&paymentType=CASH",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "paymentType must be AT_WEB or AT_HOTEL",
+		},
+		{
+			name:           "Unknown rate class",
+			queryParams:    validParams + "&rateClass=PKG",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "rateClass must be NOR or NRF",
+		},
+		{
+			name:           "Destination search",
+			queryParams:    fmt.Sprintf("destination=PMI&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:           "Geolocation search",
+			queryParams:    fmt.Sprintf("latitude=39.57&longitude=2.65&radius=20&unit=mi&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:           "No search mode",
+			queryParams:    fmt.Sprintf("checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "exactly one of hotelIds, destination or latitude/longitude is required",
+		},
+		{
+			name:           "Several search modes",
+			queryParams:    validParams + "&destination=PMI",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "exactly one of hotelIds, destination or latitude/longitude is required",
+		},
+		{
+			name:           "Missing longitude",
+			queryParams:    fmt.Sprintf("latitude=39.57&radius=20&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "latitude and longitude are both required",
+		},
+		{
+			name:           "Latitude out of range",
+			queryParams:    fmt.Sprintf("latitude=91&longitude=2.65&radius=20&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "latitude must be between -90 and 90",
+		},
+		{
+			name:           "Longitude out of range",
+			queryParams:    fmt.Sprintf("latitude=39.57&longitude=-181&radius=20&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "longitude must be between -180 and 180",
+		},
+		{
+			name:           "Radius too large",
+			queryParams:    fmt.Sprintf("latitude=39.57&longitude=2.65&radius=150&unit=mi&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "radius must be greater than 0 and at most 200 km",
+		},
+		{
+			name:           "Unknown unit",
+			queryParams:    fmt.Sprintf("latitude=39.57&longitude=2.65&radius=20&unit=ft&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", checkinDate, checkoutDate),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "unit must be km or mi",
+		},
+		{
+			name:           "Sorted and paged",
+			queryParams:    validParams + "&sort=-price&limit=10",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:           "Unknown sort",
+			queryParams:    validParams + "&sort=stars",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "sort must be one of price, -price, hotelId, distance or name",
+		},
+		{
+			name:           "Page limit too large",
+			queryParams:    validParams + "&limit=1000",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "invalid page: limit must be between 1 and 100",
+		},
+		{
+			name:           "Page limit not a number",
+			queryParams:    validParams + "&limit=abc",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "invalid page: limit must be a number",
+		},
+		{
+			name:          "Next page limit not a number",
+			queryParams:   "cursor=abc.def&limit=abc",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "invalid page: limit must be a number",
+		},
+		{
+			name:          "Invalid cursor",
+			queryParams:   "cursor=abc.def",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "invalid page: cursor signature mismatch",
+		},
+		{
+			name:           "Unknown response profile",
+			queryParams:    validParams + "&profile=compact",
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusBadRequest,
+			expectedError:  "profile must be one of full, minimal, standard",
+		},
 		{
 			name:           "Service layer error",
 			queryParams:    downstreamErr,
@@ -107,6 +271,13 @@ func TestSearchHotels(t *testing.T) {
 			expectedCode:   http.StatusInternalServerError,
 			expectedError:  "service error",
 		},
+		{
+			name:           "Supplier quota exceeded",
+			queryParams:    strings.Replace(downstreamErr, "9999", "8888", 1),
+			supplierConfig: "test-supplier-config",
+			expectedCode:   http.StatusServiceUnavailable,
+			expectedError:  "failed to search: hotelbeds daily quota exceeded",
+		},
 	}
 
 	for _, tt := range tests {
@@ -120,6 +291,12 @@ func TestSearchHotels(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedCode == http.StatusServiceUnavailable {
+				retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+				assert.NoError(t, err)
+				assert.Positive(t, retryAfter)
+				assert.LessOrEqual(t, retryAfter, int((24 * time.Hour).Seconds()))
+			}
 
 			if tt.expectedError != "" {
 				var response map[string]string
@@ -130,3 +307,203 @@ func TestSearchHotels(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchHotels_Tracing(t *testing.T) {
+	recorder := telemetrymocks.NewSpanRecorder()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(telemetry.GinMiddleware())
+	router.GET("/hotels", NewHotelsHandler(&mocks.MockHotelService{}, &mocks.MockFlexibleSearchService{}, service.NewSearchPaginator(config.Default().Search)).SearchHotels())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/hotels?hotelIds=1234&checkin=asdf&checkout=asdf&occupancies=[]&currency=EUR", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	request := telemetrymocks.SpanByName(recorder, "GET /hotels")
+	validate := telemetrymocks.SpanByName(recorder, "HotelsHandler.validate")
+	assert.NotNil(t, request)
+	assert.NotNil(t, validate)
+
+	assert.Equal(t, request.SpanContext().SpanID(), validate.Parent().SpanID())
+	assert.Equal(t, codes.Error, validate.Status().Code)
+}
+
+func TestSearchHotels_TenantSupplierConfig(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auth.WithTenant(c.Request.Context(), auth.Tenant{ID: "acme", SupplierConfig: "acme-config"}))
+	})
+	router.GET("/hotels", NewHotelsHandler(&mocks.MockHotelService{}, &mocks.MockFlexibleSearchService{}, service.NewSearchPaginator(config.Default().Search)).SearchHotels())
+
+	today := time.Now()
+	query := fmt.Sprintf("hotelIds=1234&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", today.AddDate(0, 0, 1).Format("2006-01-02"), today.AddDate(0, 0, 2).Format("2006-01-02"))
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/hotels?"+query, nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestSearchHotels_Refundable(t *testing.T) {
+	router := setupRouter()
+	today := time.Now()
+	query := fmt.Sprintf("hotelIds=1234&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", today.AddDate(0, 0, 1).Format("2006-01-02"), today.AddDate(0, 0, 2).Format("2006-01-02"))
+
+	tests := []struct {
+		name           string
+		refundable     string
+		expectedHotels int
+	}{
+		{name: "Without filter", refundable: "", expectedHotels: 1},
+		{name: "Refundable only", refundable: "&refundable=true", expectedHotels: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/hotels/search?"+query+tt.refundable, nil)
+			req.Header.Set("x-liteapi-supplier-config", "test-supplier-config")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response dto.HotelPriceResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Data, tt.expectedHotels)
+		})
+	}
+}
+
+func TestSearchHotels_Warnings(t *testing.T) {
+	router := setupRouter()
+	today := time.Now()
+	query := fmt.Sprintf("hotelIds=unmapped&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", today.AddDate(0, 0, 1).Format("2006-01-02"), today.AddDate(0, 0, 2).Format("2006-01-02"))
+
+	for _, profile := range []string{ProfileFull, ProfileMinimal} {
+		t.Run(profile, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/hotels/search?"+query+"&profile="+profile, nil)
+			req.Header.Set("x-liteapi-supplier-config", "test-supplier-config")
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response dto.HotelPriceResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Empty(t, response.Data)
+			assert.Equal(t, []string{`hotel id "unmapped" is not mapped to a hotelbeds hotel`}, response.Warnings)
+		})
+	}
+}
+
+func TestSearchHotels_Pagination(t *testing.T) {
+	router := setupRouter()
+	today := time.Now()
+	query := fmt.Sprintf("destination=PMI&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR&limit=2", today.AddDate(0, 0, 1).Format("2006-01-02"), today.AddDate(0, 0, 2).Format("2006-01-02"))
+
+	get := func(query string) dto.HotelPriceResponse {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/hotels/search?"+query, nil)
+		req.Header.Set("x-liteapi-supplier-config", "test-supplier-config")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response dto.HotelPriceResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	first := get(query)
+	assert.Len(t, first.Data, 2)
+	assert.NotEmpty(t, first.NextCursor)
+
+	second := get("cursor=" + first.NextCursor)
+	assert.Len(t, second.Data, 1)
+	assert.Equal(t, "5678", second.Data[0].HotelID)
+	assert.Empty(t, second.NextCursor)
+}
+
+func TestSearchHotels_Flexible(t *testing.T) {
+	router := setupRouter()
+	today := time.Now()
+	windowStart := today.AddDate(0, 0, 1).Format("2006-01-02")
+	windowEnd := today.AddDate(0, 0, 14).Format("2006-01-02")
+
+	query := func(hotelID, checkin, nights string) string {
+		return fmt.Sprintf("/hotels/search?flexible=true&nights=%s&hotelIds=%s&checkin=%s&checkout=%s&occupancies=[{\"adults\":2}]&currency=EUR", nights, hotelID, checkin, windowEnd)
+	}
+
+	tests := []struct {
+		name          string
+		url           string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			name:         "Success case",
+			url:          query("1234", windowStart, "3"),
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:          "Missing nights",
+			url:           query("1234", windowStart, ""),
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "nights must be at least 1 for a flexible search",
+		},
+		{
+			name:          "Limit is rejected",
+			url:           query("1234", windowStart, "3") + "&limit=10",
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "limit is not supported for a flexible search",
+		},
+		{
+			name:          "Existing date validation applies to the window",
+			url:           query("1234", "asdf", "3"),
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "check-in date must be in format YYYY-MM-DD",
+		},
+		{
+			name:          "Window rejected by the service",
+			url:           query("1234", windowStart, "31"),
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "invalid flexible search: stay too long",
+		},
+		{
+			name:          "Service error",
+			url:           query("9999", windowStart, "3"),
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: "service error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.url, nil)
+			req.Header.Set("x-liteapi-supplier-config", "test-supplier-config")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var response dto.FlexiblePriceResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Data, 1)
+			assert.Equal(t, windowStart, response.Data[0].Cheapest.CheckIn)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+)
+
+// Mock content service for testing
+type MockContentService struct{}
+
+func (m *MockContentService) HotelContent(ctx context.Context, hotelID string) (dto.HotelContent, error) {
+	switch hotelID {
+	case "1234":
+		return dto.HotelContent{
+			HotelID: "1234",
+			Name:    "Zafiro Palace",
+			Stars:   5,
+			Images:  []dto.HotelImage{{URL: "https://photos.example.com/1234.jpg", Type: "GEN"}},
+		}, nil
+	case "9999":
+		return dto.HotelContent{}, fmt.Errorf("service error")
+	default:
+		return dto.HotelContent{}, fmt.Errorf("%w: %v", service.ErrContentNotFound, hotelID)
+	}
+}
+
+func (m *MockContentService) Sync(ctx context.Context) error {
+	return nil
+}
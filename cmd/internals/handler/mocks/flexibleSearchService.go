@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+)
+
+// Mock flexible search service for testing
+type MockFlexibleSearchService struct{}
+
+func (m *MockFlexibleSearchService) SearchFlexible(ctx context.Context, params dto.FlexibleSearchServiceParams) (dto.FlexibleSearchServiceResponse, error) {
+
+	// Return error for specific hotel ID
+	if len(params.HotelIDs) > 0 && params.HotelIDs[0] == "9999" {
+		return dto.FlexibleSearchServiceResponse{}, fmt.Errorf("service error")
+	}
+
+	// Reject windows the service could not search
+	if params.Nights > 30 {
+		return dto.FlexibleSearchServiceResponse{}, fmt.Errorf("%w: stay too long", service.ErrInvalidFlexibleSearch)
+	}
+
+	cheapest := dto.CalendarPrice{CheckIn: params.WindowStart, CheckOut: params.WindowEnd, Price: 199.99}
+
+	return dto.FlexibleSearchServiceResponse{
+		Calendars: []dto.HotelCalendar{
+			{
+				HotelID:  "1234",
+				Currency: params.Currency,
+				Cheapest: cheapest,
+				Calendar: []dto.CalendarPrice{cheapest},
+			},
+		},
+	}, nil
+}
@@ -1,22 +1,55 @@
 package mocks
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
 )
 
 // Mock hotel service for testing
 type MockHotelService struct{}
 
-func (m *MockHotelService) SearchHotels(params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+func (m *MockHotelService) SearchHotels(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+	// Destination PMI has three hotels; other destination and geolocation searches find nothing
+	if params.Destination == "PMI" {
+		return dto.HotelSearchServiceResponse{
+			HotelPrices: []dto.HotelPrice{
+				{HotelID: "1234", Currency: "EUR", Price: 199.99},
+				{HotelID: "2345", Currency: "EUR", Price: 149.99},
+				{HotelID: "5678", Currency: "EUR", Price: 299.99},
+			},
+		}, nil
+	}
+
+	if len(params.HotelIDs) == 0 {
+		return dto.HotelSearchServiceResponse{}, nil
+	}
+
+	// Unmapped hotel IDs are reported as warnings
+	if params.HotelIDs[0] == "unmapped" {
+		return dto.HotelSearchServiceResponse{
+			Warnings: []string{`hotel id "unmapped" is not mapped to a hotelbeds hotel`},
+		}, nil
+	}
 
 	// Return error for specific hotel ID
-	if params.HotelIDs[0] == 9999 {
+	if params.HotelIDs[0] == "9999" {
 		return dto.HotelSearchServiceResponse{}, fmt.Errorf("service error")
 	}
 
-	if params.HotelIDs[0] == 1234 {
+	// Hotel 8888 runs into the supplier's daily quota
+	if params.HotelIDs[0] == "8888" {
+		return dto.HotelSearchServiceResponse{}, fmt.Errorf("failed to search: %w", client.ErrSupplierQuotaExceeded)
+	}
+
+	// Hotel 1234 only has non-refundable rates
+	if params.HotelIDs[0] == "1234" && params.Filters.Refundable {
+		return dto.HotelSearchServiceResponse{}, nil
+	}
+
+	if params.HotelIDs[0] == "1234" {
 		return dto.HotelSearchServiceResponse{
 			HotelPrices: []dto.HotelPrice{
 				{
@@ -0,0 +1,50 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+)
+
+// Mock search job service for testing
+type MockSearchJobService struct {
+	ShouldError bool
+	Busy        bool
+	// Submitted records the params of the last submitted job
+	Submitted dto.HotelSearchServiceParams
+}
+
+func (m *MockSearchJobService) Submit(ctx context.Context, params dto.HotelSearchServiceParams) (dto.SearchJob, error) {
+	if m.Busy {
+		return dto.SearchJob{}, service.ErrJobsBusy
+	}
+	if m.ShouldError {
+		return dto.SearchJob{}, fmt.Errorf("service error")
+	}
+
+	m.Submitted = params
+
+	return dto.SearchJob{ID: "job-1", Status: dto.JobQueued, Batches: 1, Data: []dto.HotelPrice{}}, nil
+}
+
+func (m *MockSearchJobService) Job(ctx context.Context, id string) (dto.SearchJob, error) {
+	switch id {
+	case "job-1":
+		return dto.SearchJob{
+			ID:          "job-1",
+			Status:      dto.JobRunning,
+			Progress:    0.5,
+			Batches:     2,
+			BatchesDone: 1,
+			Data:        []dto.HotelPrice{{HotelID: "1234", Currency: "EUR", Price: 199.99}},
+		}, nil
+	case "job-error":
+		return dto.SearchJob{}, fmt.Errorf("service error")
+	default:
+		return dto.SearchJob{}, fmt.Errorf("%w: %v", service.ErrJobNotFound, id)
+	}
+}
+
+func (m *MockSearchJobService) Run(ctx context.Context) {}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+)
+
+type SearchJobsHandler struct {
+	jobService service.SearchJobService
+}
+
+func NewSearchJobsHandler(jobService service.SearchJobService) *SearchJobsHandler {
+	return &SearchJobsHandler{
+		jobService: jobService,
+	}
+}
+
+// Submit validates the search like /hotels and queues it, answering 202 with the job to poll
+func (h *SearchJobsHandler) Submit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request dto.SearchJobRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			h.badRequest(c, errors.New("invalid search job: checkin, checkout and occupancies are required"))
+			return
+		}
+
+		params, err := validateSearch(c, request.QueryParams())
+		if err != nil {
+			h.badRequest(c, err)
+			return
+		}
+
+		job, err := h.jobService.Submit(c.Request.Context(), params)
+		if errors.Is(err, service.ErrJobsBusy) {
+			slog.WarnContext(c.Request.Context(), "search job rejected", slog.String("error", err.Error()))
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "search job submission failed", slog.String("error", err.Error()))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.Header("Location", c.Request.URL.Path+"/"+job.ID)
+		c.JSON(http.StatusAccepted, job)
+	}
+}
+
+// Job returns the status, progress and results so far of a job
+func (h *SearchJobsHandler) Job() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, err := h.jobService.Job(c.Request.Context(), c.Param("id"))
+		if errors.Is(err, service.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "search job lookup failed", slog.String("error", err.Error()))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, job)
+	}
+}
+
+func (h *SearchJobsHandler) badRequest(c *gin.Context, err error) {
+	slog.WarnContext(c.Request.Context(), "invalid search job request", slog.String("error", err.Error()))
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/handler/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSearchJobsRouter(jobService *mocks.MockSearchJobService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	jobsHandler := NewSearchJobsHandler(jobService)
+	router.POST("/hotels/search/jobs", jobsHandler.Submit())
+	router.GET("/hotels/search/jobs/:id", jobsHandler.Job())
+	return router
+}
+
+func TestSubmitSearchJob(t *testing.T) {
+	checkIn := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+	checkOut := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	validBody := fmt.Sprintf(`{"checkin":%q,"checkout":%q,"hotelIds":["1234","5678"],"occupancies":[{"rooms":1,"adults":2}],"currency":"EUR","boards":["BED_AND_BREAKFAST"],"sort":"price"}`, checkIn, checkOut)
+
+	tests := []struct {
+		name          string
+		jobService    *mocks.MockSearchJobService
+		body          string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			name:         "Queued",
+			jobService:   &mocks.MockSearchJobService{},
+			body:         validBody,
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:         "Lower-case currency",
+			jobService:   &mocks.MockSearchJobService{},
+			body:         strings.Replace(validBody, `"currency":"EUR"`, `"currency":"eur"`, 1),
+			expectedCode: http.StatusAccepted,
+		},
+		{
+			name:          "Malformed body",
+			jobService:    &mocks.MockSearchJobService{},
+			body:          `{"checkin":`,
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "invalid search job: checkin, checkout and occupancies are required",
+		},
+		{
+			name:          "Validated like a search",
+			jobService:    &mocks.MockSearchJobService{},
+			body:          fmt.Sprintf(`{"checkin":%q,"checkout":%q,"occupancies":[{"adults":2}],"currency":"EUR"}`, checkIn, checkOut),
+			expectedCode:  http.StatusBadRequest,
+			expectedError: "exactly one of hotelIds, destination or latitude/longitude is required",
+		},
+		{
+			name:          "Queue full",
+			jobService:    &mocks.MockSearchJobService{Busy: true},
+			body:          validBody,
+			expectedCode:  http.StatusServiceUnavailable,
+			expectedError: "too many search jobs, retry later",
+		},
+		{
+			name:          "Service error",
+			jobService:    &mocks.MockSearchJobService{ShouldError: true},
+			body:          validBody,
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: "service error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupSearchJobsRouter(tt.jobService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPost, "/hotels/search/jobs", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("x-liteapi-supplier-config", "test-supplier-config")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var job dto.SearchJob
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+			assert.Equal(t, "job-1", job.ID)
+			assert.Equal(t, dto.JobQueued, job.Status)
+			assert.Equal(t, "/hotels/search/jobs/job-1", w.Header().Get("Location"))
+
+			submitted := tt.jobService.Submitted
+			assert.Equal(t, []string{"1234", "5678"}, submitted.HotelIDs)
+			assert.Equal(t, []dto.Occupancy{{Rooms: 1, Adults: 2}}, submitted.Occupancies)
+			assert.Equal(t, []dto.Board{dto.BoardBedAndBreakfast}, submitted.Filters.Boards)
+			assert.Equal(t, dto.SortPriceAsc, submitted.Sort)
+			assert.Equal(t, "EUR", submitted.Currency)
+		})
+	}
+}
+
+func TestSearchJob(t *testing.T) {
+	tests := []struct {
+		name          string
+		id            string
+		expectedCode  int
+		expectedError string
+	}{
+		{
+			name:         "Partial results",
+			id:           "job-1",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:          "Not found",
+			id:            "expired",
+			expectedCode:  http.StatusNotFound,
+			expectedError: "search job not found: expired",
+		},
+		{
+			name:          "Service error",
+			id:            "job-error",
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: "service error",
+		},
+	}
+
+	router := setupSearchJobsRouter(&mocks.MockSearchJobService{})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/hotels/search/jobs/"+tt.id, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+				return
+			}
+
+			var job dto.SearchJob
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+			assert.Equal(t, dto.JobRunning, job.Status)
+			assert.Equal(t, 0.5, job.Progress)
+			assert.Len(t, job.Data, 1)
+		})
+	}
+}
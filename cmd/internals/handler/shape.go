@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+)
+
+// Response profiles shared by the endpoints
+const (
+	ProfileMinimal  = "minimal"
+	ProfileStandard = "standard"
+	ProfileFull     = "full"
+)
+
+// ResponseProfile selects the response fields by JSON path, with dots between keys. Paths through arrays
+// apply to every element. Include keeps only the listed fields, when set; Exclude drops the listed ones.
+type ResponseProfile struct {
+	Include []string
+	Exclude []string
+}
+
+// ResponseShaper trims an endpoint's responses to a profile, requested with the profile query param or set
+// as the tenant default, and to the item fields requested with the fields query param
+type ResponseShaper struct {
+	// itemsKey is the key of the list the fields param applies to
+	itemsKey       string
+	profiles       map[string]ResponseProfile
+	defaultProfile string
+}
+
+// ResponseShape is the validated shaping of one request
+type ResponseShape struct {
+	profile ResponseProfile
+	fields  []string
+}
+
+func NewResponseShaper(itemsKey string, profiles map[string]ResponseProfile, defaultProfile string) *ResponseShaper {
+	return &ResponseShaper{
+		itemsKey:       itemsKey,
+		profiles:       profiles,
+		defaultProfile: defaultProfile,
+	}
+}
+
+// Parse validates the shaping params of the request. Without a profile the tenant default applies, then
+// the standard profile when fields are requested, then the shaper default.
+func (s *ResponseShaper) Parse(c *gin.Context) (ResponseShape, error) {
+	var shape ResponseShape
+
+	if fields := c.Query("fields"); fields != "" {
+		for _, field := range strings.Split(fields, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				shape.fields = append(shape.fields, s.itemsKey+"."+field)
+			}
+		}
+	}
+
+	name := c.Query("profile")
+	if tenant, ok := auth.TenantFromContext(c.Request.Context()); ok && name == "" {
+		name = tenant.ResponseProfile
+	}
+	if name == "" && len(shape.fields) > 0 {
+		name = ProfileStandard
+	}
+	if name == "" {
+		name = s.defaultProfile
+	}
+
+	profile, ok := s.profiles[name]
+	if !ok {
+		return shape, fmt.Errorf("profile must be one of %v", strings.Join(s.profileNames(), ", "))
+	}
+	shape.profile = profile
+
+	return shape, nil
+}
+
+// Apply returns the response with the profile and the requested fields applied
+func (s ResponseShape) Apply(response any) (any, error) {
+	if len(s.profile.Include) == 0 && len(s.profile.Exclude) == 0 && len(s.fields) == 0 {
+		return response, nil
+	}
+
+	encoded, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers are kept as written so shaping never changes a price
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if len(s.profile.Include) > 0 {
+		value = pick(value, splitPaths(s.profile.Include))
+	}
+	value = drop(value, splitPaths(s.profile.Exclude))
+
+	if len(s.fields) > 0 {
+		value = pickItems(value, splitPaths(s.fields))
+	}
+
+	return value, nil
+}
+
+// respond writes the shaped response with status 200
+func respond(c *gin.Context, shape ResponseShape, response any) {
+	shaped, err := shape.Apply(response)
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "failed to shape response", slog.String("error", err.Error()))
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, shaped)
+}
+
+func (s *ResponseShaper) profileNames() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func splitPaths(paths []string) [][]string {
+	split := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		split = append(split, strings.Split(path, "."))
+	}
+
+	return split
+}
+
+// pick keeps only the values on the paths
+func pick(value any, paths [][]string) any {
+	switch v := value.(type) {
+	case []any:
+		for i := range v {
+			v[i] = pick(v[i], paths)
+		}
+		return v
+	case map[string]any:
+		picked := map[string]any{}
+		for key, nested := range v {
+			var rest [][]string
+			whole := false
+			for _, path := range paths {
+				if path[0] != key {
+					continue
+				}
+				if len(path) == 1 {
+					whole = true
+					break
+				}
+				rest = append(rest, path[1:])
+			}
+
+			switch {
+			case whole:
+				picked[key] = nested
+			case len(rest) > 0:
+				picked[key] = pick(nested, rest)
+			}
+		}
+		return picked
+	default:
+		return value
+	}
+}
+
+// pickItems narrows the values under the first segment of the paths and leaves the other keys alone
+func pickItems(value any, paths [][]string) any {
+	object, ok := value.(map[string]any)
+	if !ok || len(paths) == 0 {
+		return value
+	}
+
+	key := paths[0][0]
+	if items, ok := object[key]; ok {
+		rest := make([][]string, 0, len(paths))
+		for _, path := range paths {
+			if len(path) > 1 {
+				rest = append(rest, path[1:])
+			}
+		}
+		object[key] = pick(items, rest)
+	}
+
+	return object
+}
+
+// drop removes the values on the paths
+func drop(value any, paths [][]string) any {
+	switch v := value.(type) {
+	case []any:
+		for i := range v {
+			v[i] = drop(v[i], paths)
+		}
+	case map[string]any:
+		for _, path := range paths {
+			nested, ok := v[path[0]]
+			if !ok {
+				continue
+			}
+			if len(path) == 1 {
+				delete(v, path[0])
+				continue
+			}
+			v[path[0]] = drop(nested, [][]string{path[1:]})
+		}
+	}
+
+	return value
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseShaper(t *testing.T) {
+	response := dto.HotelPriceResponse{
+		Data: []dto.HotelPrice{
+			{HotelID: "1234", Name: "Zafiro Palace", Currency: "EUR", Price: 199.99, Pricing: dto.PriceBreakdown{Base: 180, Total: 199.99}},
+			{HotelID: "5678", Name: "Almudaina", Currency: "EUR", Price: 299.99, Pricing: dto.PriceBreakdown{Base: 270, Total: 299.99}},
+		},
+		Supplier:   dto.Supplier{Request: "request", Response: "response"},
+		NextCursor: "next",
+	}
+
+	tests := []struct {
+		name          string
+		query         string
+		tenant        *auth.Tenant
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "Default is full",
+			query:    "",
+			expected: `{"data":[{"hotelId":"1234","name":"Zafiro Palace","currency":"EUR","price":199.99,"nights":0,"total":0,"perNight":0,"pricing":{"base":180,"includedTaxes":0,"payableAtProperty":0,"total":199.99}},{"hotelId":"5678","name":"Almudaina","currency":"EUR","price":299.99,"nights":0,"total":0,"perNight":0,"pricing":{"base":270,"includedTaxes":0,"payableAtProperty":0,"total":299.99}}],"supplier":{"request":"request","response":"response"},"nextCursor":"next"}`,
+		},
+		{
+			name:     "Minimal profile",
+			query:    "profile=minimal",
+			expected: `{"data":[{"currency":"EUR","hotelId":"1234","price":199.99},{"currency":"EUR","hotelId":"5678","price":299.99}],"nextCursor":"next"}`,
+		},
+		{
+			name:     "Standard profile drops the supplier echo",
+			query:    "profile=standard&fields=hotelId,name",
+			expected: `{"data":[{"hotelId":"1234","name":"Zafiro Palace"},{"hotelId":"5678","name":"Almudaina"}],"nextCursor":"next"}`,
+		},
+		{
+			name:     "Fields without a profile use the standard profile",
+			query:    "fields=hotelId,pricing.total",
+			expected: `{"data":[{"hotelId":"1234","pricing":{"total":199.99}},{"hotelId":"5678","pricing":{"total":299.99}}],"nextCursor":"next"}`,
+		},
+		{
+			name:     "Fields narrow the full profile",
+			query:    "profile=full&fields=price",
+			expected: `{"data":[{"price":199.99},{"price":299.99}],"supplier":{"request":"request","response":"response"},"nextCursor":"next"}`,
+		},
+		{
+			name:     "Tenant default",
+			tenant:   &auth.Tenant{ID: "acme", ResponseProfile: ProfileMinimal},
+			expected: `{"data":[{"currency":"EUR","hotelId":"1234","price":199.99},{"currency":"EUR","hotelId":"5678","price":299.99}],"nextCursor":"next"}`,
+		},
+		{
+			name:     "Request overrides the tenant default",
+			query:    "profile=standard&fields=hotelId",
+			tenant:   &auth.Tenant{ID: "acme", ResponseProfile: ProfileMinimal},
+			expected: `{"data":[{"hotelId":"1234"},{"hotelId":"5678"}],"nextCursor":"next"}`,
+		},
+		{
+			name:          "Unknown profile",
+			query:         "profile=compact",
+			expectedError: "profile must be one of full, minimal, standard",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/hotels", func(c *gin.Context) {
+				if tt.tenant != nil {
+					c.Request = c.Request.WithContext(auth.WithTenant(c.Request.Context(), *tt.tenant))
+				}
+
+				shape, err := hotelsShaper.Parse(c)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				respond(c, shape, response)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/hotels?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			if tt.expectedError != "" {
+				var body map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, tt.expectedError, body["error"])
+				return
+			}
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.JSONEq(t, tt.expected, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+)
+
+// RateSource reports when the FX rates were last refreshed; the zero time means they are static
+type RateSource interface {
+	RatesAsOf() time.Time
+}
+
+// ConfigCheck re-validates the loaded configuration
+func ConfigCheck(cfg config.Config) Check {
+	return NewCheck("config", func(context.Context) error {
+		return cfg.Validate()
+	})
+}
+
+// CredentialsCheck verifies the Hotelbeds credentials are present
+func CredentialsCheck(cfg config.HotelBedsConfig) Check {
+	return NewCheck("credentials", func(context.Context) error {
+		if cfg.APIKey == "" || cfg.APISecret == "" {
+			return errors.New("hotelbeds API key and secret are required")
+		}
+		return nil
+	})
+}
+
+// FXRatesCheck fails when the FX rates were last refreshed more than maxAge ago; static rates are never
+// refreshed, so their age is not checked and they are reported as "static"
+func FXRatesCheck(source RateSource, maxAge time.Duration) Check {
+	return NewCheck("fxRates", func(context.Context) error {
+		if source.RatesAsOf().IsZero() {
+			return Skip("static")
+		}
+		if age := time.Since(source.RatesAsOf()); age > maxAge {
+			return fmt.Errorf("fx rates are %v old, max age is %v", age.Truncate(time.Second), maxAge)
+		}
+		return nil
+	})
+}
+
+// CircuitBreakerCheck fails while the supplier circuit is open
+func CircuitBreakerCheck(breaker *client.CircuitBreaker) Check {
+	return NewCheck("circuitBreaker", func(context.Context) error {
+		if state := breaker.State(); state == client.CircuitOpen {
+			return fmt.Errorf("hotelbeds circuit is %v", state)
+		}
+		return nil
+	})
+}
+
+// SupplierCheck pings the Hotelbeds status endpoint
+func SupplierCheck(c client.HotelBedsClient) Check {
+	return NewCheck("hotelbeds", c.Status)
+}
@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// skipped is returned by a check that does not apply; it is reported with its status and never fails readiness
+type skipped struct {
+	status string
+}
+
+func (s *skipped) Error() string {
+	return "check skipped: " + s.status
+}
+
+// Skip reports the check as skipped with status, e.g. "static" for rates nothing refreshes
+func Skip(status string) error {
+	return &skipped{status: status}
+}
+
+// Check is a single readiness dependency
+type Check interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// ComponentStatus represents the outcome of one check
+type ComponentStatus struct {
+	Status    string    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Report represents the aggregated readiness of the service
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components"`
+}
+
+type checkFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewCheck wraps fn as a named check
+func NewCheck(name string, fn func(ctx context.Context) error) Check {
+	return &checkFunc{name: name, fn: fn}
+}
+
+func (c *checkFunc) Name() string {
+	return c.name
+}
+
+func (c *checkFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// cachedCheck reuses the last result of an expensive check until it is older than ttl
+type cachedCheck struct {
+	check     Check
+	ttl       time.Duration
+	timeout   time.Duration
+	mu        sync.Mutex
+	err       error
+	checkedAt time.Time
+	now       func() time.Time
+}
+
+// Cached runs check at most once per ttl, bounding each run by timeout
+func Cached(check Check, ttl, timeout time.Duration) Check {
+	return &cachedCheck{check: check, ttl: ttl, timeout: timeout, now: time.Now}
+}
+
+func (c *cachedCheck) Name() string {
+	return c.check.Name()
+}
+
+func (c *cachedCheck) Check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkedAt.IsZero() && c.now().Sub(c.checkedAt) < c.ttl {
+		return c.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	c.err = c.check.Check(ctx)
+	c.checkedAt = c.now()
+
+	return c.err
+}
+
+// Checker runs the readiness checks; deep checks only run when explicitly requested
+type Checker struct {
+	checks []Check
+	deep   []Check
+}
+
+func NewChecker(checks []Check, deep []Check) *Checker {
+	return &Checker{checks: checks, deep: deep}
+}
+
+// Run executes the checks concurrently and reports the service as down if any of them fails
+func (c *Checker) Run(ctx context.Context, deep bool) Report {
+	checks := c.checks
+	if deep {
+		checks = append(append([]Check{}, c.checks...), c.deep...)
+	}
+
+	report := Report{
+		Status:     StatusUp,
+		Components: make(map[string]ComponentStatus, len(checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+
+			status := ComponentStatus{Status: StatusUp}
+			var skip *skipped
+			if err := check.Check(ctx); errors.As(err, &skip) {
+				status.Status = skip.status
+			} else if err != nil {
+				status.Status = StatusDown
+				status.Error = err.Error()
+			}
+			status.CheckedAt = time.Now().UTC()
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Components[check.Name()] = status
+			if status.Status == StatusDown {
+				report.Status = StatusDown
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	return report
+}
@@ -0,0 +1,140 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticRates struct {
+	asOf time.Time
+}
+
+func (s staticRates) RatesAsOf() time.Time {
+	return s.asOf
+}
+
+func TestChecker_Run(t *testing.T) {
+	up := NewCheck("up", func(context.Context) error { return nil })
+	down := NewCheck("down", func(context.Context) error { return errors.New("broken") })
+	static := FXRatesCheck(staticRates{}, time.Hour)
+
+	tests := []struct {
+		name           string
+		checker        *Checker
+		deep           bool
+		expectedStatus string
+		expectedNames  []string
+	}{
+		{
+			name:           "All up",
+			checker:        NewChecker([]Check{up}, []Check{down}),
+			expectedStatus: StatusUp,
+			expectedNames:  []string{"up"},
+		},
+		{
+			name:           "Deep check failing",
+			checker:        NewChecker([]Check{up}, []Check{down}),
+			deep:           true,
+			expectedStatus: StatusDown,
+			expectedNames:  []string{"up", "down"},
+		},
+		{
+			name:           "Skipped check",
+			checker:        NewChecker([]Check{up, static}, nil),
+			expectedStatus: StatusUp,
+			expectedNames:  []string{"up", "fxRates"},
+		},
+		{
+			name:           "No checks",
+			checker:        NewChecker(nil, nil),
+			expectedStatus: StatusUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := tt.checker.Run(context.Background(), tt.deep)
+
+			assert.Equal(t, tt.expectedStatus, report.Status)
+			assert.Len(t, report.Components, len(tt.expectedNames))
+			for _, name := range tt.expectedNames {
+				assert.Contains(t, report.Components, name)
+			}
+			if status, ok := report.Components["down"]; ok {
+				assert.Equal(t, StatusDown, status.Status)
+				assert.Equal(t, "broken", status.Error)
+			}
+			if status, ok := report.Components["fxRates"]; ok {
+				assert.Equal(t, "static", status.Status)
+				assert.Empty(t, status.Error)
+			}
+		})
+	}
+}
+
+func TestCached(t *testing.T) {
+	calls := 0
+	check := Cached(NewCheck("supplier", func(context.Context) error {
+		calls++
+		return errors.New("unreachable")
+	}), time.Minute, time.Second)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	check.(*cachedCheck).now = func() time.Time { return now }
+
+	assert.Equal(t, "supplier", check.Name())
+	assert.Error(t, check.Check(context.Background()))
+	assert.Error(t, check.Check(context.Background()))
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Minute)
+	assert.Error(t, check.Check(context.Background()))
+	assert.Equal(t, 2, calls)
+}
+
+func TestChecks(t *testing.T) {
+	validConfig := config.Default()
+	validConfig.HotelBeds.APIKey = "key"
+	validConfig.HotelBeds.APISecret = "secret"
+
+	openBreaker := client.NewCircuitBreaker(config.CircuitBreakerConfig{
+		FailureThreshold: 1,
+		OpenTimeout:      config.Duration{Duration: time.Minute},
+	})
+	openBreaker.Failure()
+
+	tests := []struct {
+		name    string
+		check   Check
+		wantErr bool
+	}{
+		{name: "Valid config", check: ConfigCheck(validConfig)},
+		{name: "Invalid config", check: ConfigCheck(config.Default()), wantErr: true},
+		{name: "Credentials present", check: CredentialsCheck(validConfig.HotelBeds)},
+		{name: "Credentials missing", check: CredentialsCheck(config.HotelBedsConfig{}), wantErr: true},
+		{name: "Fresh FX rates", check: FXRatesCheck(staticRates{time.Now()}, time.Hour)},
+		{name: "Stale FX rates", check: FXRatesCheck(staticRates{time.Now().Add(-2 * time.Hour)}, time.Hour), wantErr: true},
+		{name: "Circuit closed", check: CircuitBreakerCheck(client.NewCircuitBreaker(config.CircuitBreakerConfig{}))},
+		{name: "Circuit open", check: CircuitBreakerCheck(openBreaker), wantErr: true},
+		{name: "Supplier up", check: SupplierCheck(&mocks.MockHotelBedsClient{})},
+		{name: "Supplier down", check: SupplierCheck(&mocks.MockHotelBedsClient{ShouldError: true}), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check.Check(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package idmap
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SupplierHotelbeds names the Hotelbeds codes in the mapping file
+const SupplierHotelbeds = "hotelbeds"
+
+// Mapper translates liteAPI hotel IDs to supplier hotel codes and back
+type Mapper interface {
+	SupplierCode(supplier, hotelID string) (string, bool)
+	HotelID(supplier, code string) (string, bool)
+}
+
+// Identity returns the mapper used without a mapping file: liteAPI IDs are the supplier codes
+func Identity() Mapper {
+	return identityMapper{}
+}
+
+type identityMapper struct{}
+
+func (identityMapper) SupplierCode(_, hotelID string) (string, bool) {
+	return hotelID, true
+}
+
+func (identityMapper) HotelID(_, code string) (string, bool) {
+	return code, true
+}
+
+// table holds both directions of the mapping, per supplier
+type table struct {
+	codes  map[string]map[string]string
+	hotels map[string]map[string]string
+	size   int
+}
+
+// jsonFile represents a JSON mapping file
+type jsonFile struct {
+	Hotels []struct {
+		HotelID   string            `json:"hotelId"`
+		Suppliers map[string]string `json:"suppliers"`
+	} `json:"hotels"`
+}
+
+// FileMapper serves the mapping loaded from a CSV or JSON file and reloads it when the file changes
+type FileMapper struct {
+	path    string
+	table   atomic.Pointer[table]
+	modTime time.Time
+	size    int64
+}
+
+// NewFileMapper loads and validates the mapping file at path, choosing the format from its extension
+func NewFileMapper(path string) (*FileMapper, error) {
+	m := &FileMapper{path: path}
+	if _, err := m.Reload(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *FileMapper) SupplierCode(supplier, hotelID string) (string, bool) {
+	code, ok := m.table.Load().codes[supplier][hotelID]
+	return code, ok
+}
+
+func (m *FileMapper) HotelID(supplier, code string) (string, bool) {
+	hotelID, ok := m.table.Load().hotels[supplier][code]
+	return hotelID, ok
+}
+
+// Len returns the number of mapped hotels
+func (m *FileMapper) Len() int {
+	return m.table.Load().size
+}
+
+// Reload reads the file again if it changed since the last load and reports whether it did. An invalid
+// file is rejected as a whole, so the previous mapping stays in use.
+func (m *FileMapper) Reload() (bool, error) {
+	info, err := os.Stat(m.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read id mapping: %w", err)
+	}
+
+	if m.table.Load() != nil && info.ModTime().Equal(m.modTime) && info.Size() == m.size {
+		return false, nil
+	}
+
+	content, err := os.ReadFile(m.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read id mapping: %w", err)
+	}
+
+	loaded, err := parse(m.path, content)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse id mapping %v: %w", m.path, err)
+	}
+
+	m.table.Store(loaded)
+	m.modTime, m.size = info.ModTime(), info.Size()
+
+	return true, nil
+}
+
+// Watch checks the file every interval and reloads it when it changed, until ctx is done
+func (m *FileMapper) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := m.Reload()
+		if err != nil {
+			slog.ErrorContext(ctx, "id mapping reload failed, keeping the previous mapping", slog.String("error", err.Error()))
+			continue
+		}
+		if reloaded {
+			slog.InfoContext(ctx, "id mapping reloaded", slog.Int("hotels", m.Len()))
+		}
+	}
+}
+
+func parse(path string, content []byte) (*table, error) {
+	t := &table{codes: map[string]map[string]string{}, hotels: map[string]map[string]string{}}
+	hotels := map[string]bool{}
+
+	add := func(line int, hotelID, supplier, code string) error {
+		hotelID, supplier, code = strings.TrimSpace(hotelID), strings.ToLower(strings.TrimSpace(supplier)), strings.TrimSpace(code)
+		if hotelID == "" || supplier == "" || code == "" {
+			return fmt.Errorf("entry %d: hotelId, supplier and code are required", line)
+		}
+
+		if t.codes[supplier] == nil {
+			t.codes[supplier] = map[string]string{}
+			t.hotels[supplier] = map[string]string{}
+		}
+		if _, ok := t.codes[supplier][hotelID]; ok {
+			return fmt.Errorf("entry %d: hotel %v is mapped twice to %v", line, hotelID, supplier)
+		}
+		if _, ok := t.hotels[supplier][code]; ok {
+			return fmt.Errorf("entry %d: %v code %v is mapped to two hotels", line, supplier, code)
+		}
+
+		t.codes[supplier][hotelID] = code
+		t.hotels[supplier][code] = hotelID
+		hotels[hotelID] = true
+
+		return nil
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		var file jsonFile
+		if err := json.Unmarshal(content, &file); err != nil {
+			return nil, err
+		}
+
+		for i, hotel := range file.Hotels {
+			if len(hotel.Suppliers) == 0 {
+				return nil, fmt.Errorf("entry %d: hotel %v has no supplier codes", i+1, hotel.HotelID)
+			}
+			for supplier, code := range hotel.Suppliers {
+				if err := add(i+1, hotel.HotelID, supplier, code); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case ".csv":
+		// CSV files have a hotelId,supplier,code header and one supplier code per row
+		reader := csv.NewReader(bytes.NewReader(content))
+		reader.FieldsPerRecord = 3
+		reader.TrimLeadingSpace = true
+
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("missing header: %w", err)
+		}
+		if strings.Join(header, ",") != "hotelId,supplier,code" {
+			return nil, errors.New("header must be hotelId,supplier,code")
+		}
+
+		for line := 2; ; line++ {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if err := add(line, record[0], record[1], record[2]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported format, use .csv or .json")
+	}
+
+	t.size = len(hotels)
+
+	return t, nil
+}
@@ -0,0 +1,134 @@
+package idmap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeMapping(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestNewFileMapper(t *testing.T) {
+	tests := []struct {
+		name          string
+		file          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:    "CSV",
+			file:    "ids.csv",
+			content: "hotelId,supplier,code\nlp1a2b,hotelbeds,1234\nlp3c4d,Hotelbeds,5678\n",
+		},
+		{
+			name:    "JSON",
+			file:    "ids.json",
+			content: `{"hotels":[{"hotelId":"lp1a2b","suppliers":{"hotelbeds":"1234"}},{"hotelId":"lp3c4d","suppliers":{"hotelbeds":"5678"}}]}`,
+		},
+		{
+			name:          "CSV without header",
+			file:          "ids.csv",
+			content:       "lp1a2b,hotelbeds,1234\n",
+			expectedError: "header must be hotelId,supplier,code",
+		},
+		{
+			name:          "Hotel mapped twice",
+			file:          "ids.csv",
+			content:       "hotelId,supplier,code\nlp1a2b,hotelbeds,1234\nlp1a2b,hotelbeds,5678\n",
+			expectedError: "entry 3: hotel lp1a2b is mapped twice to hotelbeds",
+		},
+		{
+			name:          "Code mapped to two hotels",
+			file:          "ids.json",
+			content:       `{"hotels":[{"hotelId":"lp1a2b","suppliers":{"hotelbeds":"1234"}},{"hotelId":"lp3c4d","suppliers":{"hotelbeds":"1234"}}]}`,
+			expectedError: "entry 2: hotelbeds code 1234 is mapped to two hotels",
+		},
+		{
+			name:          "Missing code",
+			file:          "ids.csv",
+			content:       "hotelId,supplier,code\nlp1a2b,hotelbeds,\n",
+			expectedError: "entry 2: hotelId, supplier and code are required",
+		},
+		{
+			name:          "Unsupported format",
+			file:          "ids.yaml",
+			content:       "hotels: []",
+			expectedError: "unsupported format",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeMapping(t, t.TempDir(), tt.file, tt.content)
+
+			mapper, err := NewFileMapper(path)
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, mapper.Len())
+
+			code, ok := mapper.SupplierCode(SupplierHotelbeds, "lp3c4d")
+			assert.True(t, ok)
+			assert.Equal(t, "5678", code)
+
+			hotelID, ok := mapper.HotelID(SupplierHotelbeds, "1234")
+			assert.True(t, ok)
+			assert.Equal(t, "lp1a2b", hotelID)
+
+			_, ok = mapper.SupplierCode(SupplierHotelbeds, "unknown")
+			assert.False(t, ok)
+			_, ok = mapper.HotelID(SupplierHotelbeds, "9999")
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFileMapper_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := writeMapping(t, dir, "ids.csv", "hotelId,supplier,code\nlp1a2b,hotelbeds,1234\n")
+
+	mapper, err := NewFileMapper(path)
+	assert.NoError(t, err)
+
+	reloaded, err := mapper.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded, "an unchanged file is not read again")
+
+	writeMapping(t, dir, "ids.csv", "hotelId,supplier,code\nlp1a2b,hotelbeds,4321\nlp3c4d,hotelbeds,5678\n")
+	reloaded, err = mapper.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, 2, mapper.Len())
+	code, _ := mapper.SupplierCode(SupplierHotelbeds, "lp1a2b")
+	assert.Equal(t, "4321", code)
+
+	// An invalid file is rejected and the previous mapping kept
+	writeMapping(t, dir, "ids.csv", "hotelId,supplier,code\nlp1a2b,hotelbeds,1\nlp1a2b,hotelbeds,2\n")
+	assert.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	_, err = mapper.Reload()
+	assert.Error(t, err)
+	code, _ = mapper.SupplierCode(SupplierHotelbeds, "lp1a2b")
+	assert.Equal(t, "4321", code)
+}
+
+func TestIdentity(t *testing.T) {
+	mapper := Identity()
+
+	code, ok := mapper.SupplierCode(SupplierHotelbeds, "1234")
+	assert.True(t, ok)
+	assert.Equal(t, "1234", code)
+
+	hotelID, ok := mapper.HotelID(SupplierHotelbeds, "1234")
+	assert.True(t, ok)
+	assert.Equal(t, "1234", hotelID)
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+)
+
+const (
+	KeyRequestID = "request_id"
+
+	redactedValue = "[REDACTED]"
+)
+
+// sensitiveKeys are attribute keys whose values must never reach the logs, compared case-insensitively
+var sensitiveKeys = map[string]struct{}{
+	strings.ToLower(util.HeaderApiKey):         {},
+	strings.ToLower(util.HeaderSignature):      {},
+	strings.ToLower(util.HeaderSupplierConfig): {},
+	strings.ToLower(util.HeaderLiteAPIKey):     {},
+	"apikey":                                   {},
+	"apisecret":                                {},
+	"secret":                                   {},
+}
+
+type requestIDKey struct{}
+
+// NewLogger creates a JSON logger that redacts secrets and tags every record with the request ID found in its context
+func NewLogger(w io.Writer, level slog.Level) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})
+
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel converts a level name (debug, info, warn, error) into a slog.Level
+func ParseLevel(level string) (slog.Level, error) {
+	var l slog.Level
+	if level == "" {
+		return slog.LevelInfo, nil
+	}
+
+	if err := l.UnmarshalText([]byte(level)); err != nil {
+		return l, fmt.Errorf("invalid log level: %v", level)
+	}
+
+	return l, nil
+}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// Headers groups the HTTP headers into a single attribute; sensitive headers are redacted by the logger
+func Headers(key string, headers http.Header) slog.Attr {
+	attrs := make([]any, 0, len(headers))
+	for name := range headers {
+		attrs = append(attrs, slog.String(name, headers.Get(name)))
+	}
+
+	return slog.Group(key, attrs...)
+}
+
+// redact replaces the value of any sensitive attribute, including those nested in groups
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if _, ok := sensitiveKeys[strings.ToLower(a.Key)]; ok {
+		return slog.String(a.Key, redactedValue)
+	}
+
+	return a
+}
+
+// contextHandler adds the request ID from the record's context to every record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		r.AddAttrs(slog.String(KeyRequestID, requestID))
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
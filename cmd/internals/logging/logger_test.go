@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLogger_RequestID(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, slog.LevelInfo)
+
+	ctx := WithRequestID(context.Background(), "req-123")
+	logger.With(slog.String("component", "test")).InfoContext(ctx, "hello")
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "hello", line["msg"])
+	assert.Equal(t, "req-123", line[KeyRequestID])
+	assert.Equal(t, "test", line["component"])
+}
+
+func TestNewLogger_Redaction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := NewLogger(buf, slog.LevelInfo)
+
+	headers := http.Header{}
+	headers.Set(util.HeaderApiKey, "my-api-key")
+	headers.Set(util.HeaderSignature, "my-signature")
+	headers.Set(util.HeaderSupplierConfig, "my-supplier-config")
+	headers.Set(util.HeaderAccept, util.ValueApplicationJSON)
+
+	logger.Info("request", Headers("headers", headers), slog.String("apiSecret", "my-secret"))
+
+	assert.NotContains(t, buf.String(), "my-api-key")
+	assert.NotContains(t, buf.String(), "my-signature")
+	assert.NotContains(t, buf.String(), "my-supplier-config")
+	assert.NotContains(t, buf.String(), "my-secret")
+
+	var line struct {
+		Headers   map[string]string `json:"headers"`
+		ApiSecret string            `json:"apiSecret"`
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, redactedValue, line.Headers["Api-Key"])
+	assert.Equal(t, redactedValue, line.Headers["X-Signature"])
+	assert.Equal(t, redactedValue, line.Headers["X-Liteapi-Supplier-Config"])
+	assert.Equal(t, util.ValueApplicationJSON, line.Headers["Accept"])
+	assert.Equal(t, redactedValue, line.ApiSecret)
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		level    string
+		expected slog.Level
+		wantErr  bool
+	}{
+		{name: "Empty defaults to info", level: "", expected: slog.LevelInfo},
+		{name: "Debug", level: "debug", expected: slog.LevelDebug},
+		{name: "Uppercase warn", level: "WARN", expected: slog.LevelWarn},
+		{name: "Invalid", level: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, err := ParseLevel(tt.level)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
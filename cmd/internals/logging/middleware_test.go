@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name      string
+		requestID string
+		generated bool
+	}{
+		{name: "Accepts caller request ID", requestID: "abc-123"},
+		{name: "Generates missing request ID", requestID: "", generated: true},
+		{name: "Replaces invalid request ID", requestID: "bad id\n", generated: true},
+		{name: "Replaces oversized request ID", requestID: strings.Repeat("a", maxRequestIDLength+1), generated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			router := gin.New()
+			router.Use(RequestIDMiddleware())
+			router.GET("/", func(c *gin.Context) {
+				fromContext = RequestIDFromContext(c.Request.Context())
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			req.Header.Set(util.HeaderRequestID, tt.requestID)
+			router.ServeHTTP(w, req)
+
+			echoed := w.Header().Get(util.HeaderRequestID)
+			assert.NotEmpty(t, echoed)
+			assert.Equal(t, echoed, fromContext)
+			if tt.generated {
+				assert.NotEqual(t, tt.requestID, echoed)
+				assert.Len(t, echoed, 32)
+			} else {
+				assert.Equal(t, tt.requestID, echoed)
+			}
+		})
+	}
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	buf := &bytes.Buffer{}
+	previous := slog.Default()
+	slog.SetDefault(NewLogger(buf, slog.LevelInfo))
+	defer slog.SetDefault(previous)
+
+	router := gin.New()
+	router.Use(RequestIDMiddleware(), AccessLogMiddleware())
+	router.GET("/hotels", func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/hotels", nil)
+	req.Header.Set(util.HeaderRequestID, "req-1")
+	router.ServeHTTP(w, req)
+
+	var line map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "ERROR", line["level"])
+	assert.Equal(t, "req-1", line[KeyRequestID])
+	assert.Equal(t, "/hotels", line["path"])
+	assert.Equal(t, float64(http.StatusInternalServerError), line["status"])
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Decision represents the outcome of taking a token from a bucket
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// TokenBucket refills at rate tokens per second up to burst tokens
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+func NewTokenBucket(rate float64, burst int, now time.Time) *TokenBucket {
+	return &TokenBucket{
+		rate:   rate,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   now,
+	}
+}
+
+// Take consumes one token if available and reports how long to wait otherwise
+func (b *TokenBucket) Take(now time.Time) Decision {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+
+	decision := Decision{Limit: b.burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		decision.Allowed = true
+	} else {
+		decision.RetryAfter = b.durationFor(1 - b.tokens)
+	}
+
+	decision.Remaining = int(math.Floor(b.tokens))
+	decision.Reset = b.durationFor(float64(b.burst) - b.tokens)
+
+	return decision
+}
+
+func (b *TokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+
+	b.tokens = math.Min(float64(b.burst), b.tokens+elapsed*b.rate)
+	b.last = now
+}
+
+func (b *TokenBucket) durationFor(tokens float64) time.Duration {
+	if tokens <= 0 || b.rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / b.rate * float64(time.Second))
+}
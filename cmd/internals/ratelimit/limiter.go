@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit represents a token bucket rate and burst
+type Limit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type keyedBucket struct {
+	bucket   *TokenBucket
+	limit    Limit
+	lastUsed time.Time
+}
+
+// KeyedLimiter keeps one token bucket per key, e.g. per tenant, and drops buckets that stay unused
+type KeyedLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*keyedBucket
+	idleTTL time.Duration
+	lastGC  time.Time
+	now     func() time.Time
+}
+
+func NewKeyedLimiter(idleTTL time.Duration) *KeyedLimiter {
+	return &KeyedLimiter{
+		buckets: map[string]*keyedBucket{},
+		idleTTL: idleTTL,
+		now:     time.Now,
+	}
+}
+
+// Take consumes a token from the bucket for key, creating it with limit on first use.
+// A bucket is recreated when the limit for its key changes.
+func (l *KeyedLimiter) Take(key string, limit Limit) Decision {
+	now := l.now()
+
+	l.mu.Lock()
+	l.collect(now)
+
+	entry, ok := l.buckets[key]
+	if !ok || entry.limit != limit {
+		entry = &keyedBucket{
+			bucket: NewTokenBucket(limit.RequestsPerSecond, limit.Burst, now),
+			limit:  limit,
+		}
+		l.buckets[key] = entry
+	}
+	entry.lastUsed = now
+	l.mu.Unlock()
+
+	return entry.bucket.Take(now)
+}
+
+// collect drops idle buckets at most once per idle TTL
+func (l *KeyedLimiter) collect(now time.Time) {
+	if now.Sub(l.lastGC) < l.idleTTL {
+		return
+	}
+
+	for key, entry := range l.buckets {
+		if now.Sub(entry.lastUsed) >= l.idleTTL {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastGC = now
+}
@@ -0,0 +1,54 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bucket := NewTokenBucket(2, 2, now)
+
+	first := bucket.Take(now)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 2, first.Limit)
+	assert.Equal(t, 1, first.Remaining)
+
+	second := bucket.Take(now)
+	assert.True(t, second.Allowed)
+	assert.Equal(t, 0, second.Remaining)
+	assert.Equal(t, time.Second, second.Reset)
+
+	denied := bucket.Take(now)
+	assert.False(t, denied.Allowed)
+	assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+
+	// Half a second later one token has been refilled
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, bucket.Take(now).Allowed)
+	assert.False(t, bucket.Take(now).Allowed)
+}
+
+func TestKeyedLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewKeyedLimiter(time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	limit := Limit{RequestsPerSecond: 1, Burst: 1}
+
+	assert.True(t, limiter.Take("a", limit).Allowed)
+	assert.False(t, limiter.Take("a", limit).Allowed)
+
+	// Keys are limited independently
+	assert.True(t, limiter.Take("b", limit).Allowed)
+
+	// Changing the limit for a key replaces its bucket
+	assert.True(t, limiter.Take("a", Limit{RequestsPerSecond: 1, Burst: 5}).Allowed)
+
+	// Idle buckets are dropped
+	now = now.Add(2 * time.Minute)
+	limiter.Take("c", limit)
+	assert.Len(t, limiter.buckets, 1)
+}
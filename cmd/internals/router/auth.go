@@ -0,0 +1,44 @@
+package router
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+)
+
+// AuthMiddleware validates the liteAPI key and attaches the owning tenant to the request context, where
+// handlers and services read it with auth.TenantFromContext
+func AuthMiddleware(store auth.KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := c.GetHeader(util.HeaderLiteAPIKey)
+		if apiKey == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "API key is required",
+			})
+			return
+		}
+
+		// An invalid key is a bad credential; a known but disabled key is authenticated and refused
+		tenant, err := store.Lookup(apiKey)
+		if err != nil {
+			status, message := http.StatusUnauthorized, auth.ErrUnknownKey.Error()
+			if errors.Is(err, auth.ErrDisabledKey) {
+				status, message = http.StatusForbidden, err.Error()
+			}
+
+			slog.WarnContext(c.Request.Context(), "rejected API key", slog.String("error", err.Error()))
+			c.AbortWithStatusJSON(status, gin.H{
+				"error": message,
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithTenant(c.Request.Context(), tenant))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,72 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockKeyStore map[string]error
+
+func (m mockKeyStore) Lookup(apiKey string) (auth.Tenant, error) {
+	err, ok := m[apiKey]
+	if !ok {
+		return auth.Tenant{}, auth.ErrUnknownKey
+	}
+	if err != nil {
+		return auth.Tenant{}, err
+	}
+	return auth.Tenant{ID: "tenant-" + apiKey}, nil
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := mockKeyStore{"valid": nil, "revoked": auth.ErrDisabledKey}
+
+	tests := []struct {
+		name           string
+		apiKey         string
+		expectedCode   int
+		expectedError  string
+		expectedTenant string
+	}{
+		{name: "Valid key", apiKey: "valid", expectedCode: http.StatusOK, expectedTenant: "tenant-valid"},
+		{name: "Missing key", apiKey: "", expectedCode: http.StatusUnauthorized, expectedError: "API key is required"},
+		{name: "Unknown key", apiKey: "unknown", expectedCode: http.StatusUnauthorized, expectedError: "invalid API key"},
+		{name: "Disabled key", apiKey: "revoked", expectedCode: http.StatusForbidden, expectedError: "API key is disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AuthMiddleware(store))
+			router.GET("/", func(c *gin.Context) {
+				tenant, ok := auth.TenantFromContext(c.Request.Context())
+				assert.True(t, ok)
+				c.String(http.StatusOK, tenant.ID)
+			})
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(util.HeaderLiteAPIKey, tt.apiKey)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["error"])
+			} else {
+				assert.Equal(t, tt.expectedTenant, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package router
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/ratelimit"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+)
+
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset"
+
+	rateLimitIdleTTL = 10 * time.Minute
+)
+
+// RateLimitMiddleware enforces a token bucket per tenant, falling back to the API key or client IP
+// for unauthenticated requests, and a global bucket shared by all callers
+func RateLimitMiddleware(cfg config.RateLimitConfig) gin.HandlerFunc {
+	limiter := ratelimit.NewKeyedLimiter(rateLimitIdleTTL)
+	global := ratelimit.NewTokenBucket(cfg.GlobalRPS, cfg.GlobalBurst, time.Now())
+	defaultLimit := ratelimit.Limit{RequestsPerSecond: cfg.TenantRPS, Burst: cfg.TenantBurst}
+
+	return func(c *gin.Context) {
+		key, limit := rateLimitKey(c, defaultLimit)
+
+		decision := limiter.Take(key, limit)
+		if decision.Allowed {
+			if globalDecision := global.Take(time.Now()); !globalDecision.Allowed {
+				decision.Allowed = false
+				decision.RetryAfter = globalDecision.RetryAfter
+			}
+		}
+
+		c.Header(HeaderRateLimitLimit, strconv.Itoa(decision.Limit))
+		c.Header(HeaderRateLimitRemaining, strconv.Itoa(decision.Remaining))
+		c.Header(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(decision.Reset)))
+
+		if !decision.Allowed {
+			slog.WarnContext(c.Request.Context(), "rate limit exceeded", slog.String("key", key))
+			c.Header(util.HeaderRetryAfter, strconv.Itoa(max(1, ceilSeconds(decision.RetryAfter))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "rate limit exceeded",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitKey returns the bucket key and limit for the caller
+func rateLimitKey(c *gin.Context, defaultLimit ratelimit.Limit) (string, ratelimit.Limit) {
+	if tenant, ok := auth.TenantFromContext(c.Request.Context()); ok {
+		limit := defaultLimit
+		if tenant.RequestsPerSecond > 0 {
+			limit.RequestsPerSecond = tenant.RequestsPerSecond
+		}
+		if tenant.Burst > 0 {
+			limit.Burst = tenant.Burst
+		}
+		return fmt.Sprintf("tenant:%s", tenant.ID), limit
+	}
+
+	if apiKey := c.GetHeader(util.HeaderLiteAPIKey); apiKey != "" {
+		return fmt.Sprintf("key:%s", auth.HashKey(apiKey)), defaultLimit
+	}
+
+	return fmt.Sprintf("ip:%s", c.ClientIP()), defaultLimit
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,82 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRateLimitRouter(cfg config.RateLimitConfig, tenant *auth.Tenant) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if tenant != nil {
+		router.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(auth.WithTenant(c.Request.Context(), *tenant))
+		})
+	}
+	router.Use(RateLimitMiddleware(cfg))
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func serve(router *gin.Engine, remoteAddr string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.RemoteAddr = remoteAddr
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	cfg := config.RateLimitConfig{Enabled: true, TenantRPS: 1, TenantBurst: 2, GlobalRPS: 100, GlobalBurst: 100}
+	router := setupRateLimitRouter(cfg, nil)
+
+	first := serve(router, "10.0.0.1:1234")
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "2", first.Header().Get(HeaderRateLimitLimit))
+	assert.Equal(t, "1", first.Header().Get(HeaderRateLimitRemaining))
+
+	assert.Equal(t, http.StatusOK, serve(router, "10.0.0.1:1234").Code)
+
+	limited := serve(router, "10.0.0.1:1234")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "1", limited.Header().Get(util.HeaderRetryAfter))
+	assert.Equal(t, "0", limited.Header().Get(HeaderRateLimitRemaining))
+	assert.Equal(t, "2", limited.Header().Get(HeaderRateLimitReset))
+
+	var response map[string]string
+	assert.NoError(t, json.Unmarshal(limited.Body.Bytes(), &response))
+	assert.Equal(t, "rate limit exceeded", response["error"])
+
+	// Other callers have their own bucket
+	assert.Equal(t, http.StatusOK, serve(router, "10.0.0.2:1234").Code)
+}
+
+func TestRateLimitMiddleware_Global(t *testing.T) {
+	cfg := config.RateLimitConfig{Enabled: true, TenantRPS: 100, TenantBurst: 100, GlobalRPS: 1, GlobalBurst: 1}
+	router := setupRateLimitRouter(cfg, nil)
+
+	assert.Equal(t, http.StatusOK, serve(router, "10.0.0.1:1234").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, "10.0.0.2:1234").Code)
+}
+
+func TestRateLimitMiddleware_TenantOverride(t *testing.T) {
+	cfg := config.RateLimitConfig{Enabled: true, TenantRPS: 1, TenantBurst: 1, GlobalRPS: 100, GlobalBurst: 100}
+	router := setupRateLimitRouter(cfg, &auth.Tenant{ID: "acme", RequestsPerSecond: 10, Burst: 3})
+
+	for i := 0; i < 3; i++ {
+		w := serve(router, "10.0.0.1:1234")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "3", w.Header().Get(HeaderRateLimitLimit))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve(router, "10.0.0.2:1234").Code)
+}
@@ -1,27 +1,111 @@
 package router
 
 import (
+	"log/slog"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/handler"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/health"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/logging"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
 )
 
+// Options carries everything the router needs; all dependencies are built by the caller
+type Options struct {
+	Config                config.Config
+	HotelService          service.HotelService
+	FlexibleSearchService service.FlexibleSearchService
+	SearchPaginator       service.SearchPaginator
+	// ContentService serves hotel static content; the content endpoint is not registered without it
+	ContentService service.ContentService
+	// CurrencyService serves the currency endpoints; they are not registered without it
+	CurrencyService service.CurrencyService
+	// SearchJobService runs asynchronous searches; the job endpoints are not registered without it
+	SearchJobService service.SearchJobService
+	HealthChecker    *health.Checker
+	KeyStore         auth.KeyStore
+	Middleware       []gin.HandlerFunc
+}
+
 type Router struct {
-	engine *gin.Engine
+	engine  *gin.Engine
+	options Options
 }
 
 func (r *Router) Setup() *gin.Engine {
+	r.engine.Use(r.options.Middleware...)
 
-	// Health endpoint
-	r.engine.GET("/health", handler.NewHealthHandler().Handle())
+	// Health endpoints
+	healthHandler := handler.NewHealthHandler(r.options.HealthChecker)
+	r.engine.GET("/health", healthHandler.Handle())
+	r.engine.GET("/health/live", healthHandler.Live())
+	r.engine.GET("/health/ready", healthHandler.Ready())
+
+	// API endpoints require a liteAPI key when a keystore is configured and are rate limited per tenant
+	api := r.engine.Group("/")
+	if r.options.KeyStore != nil {
+		api.Use(AuthMiddleware(r.options.KeyStore))
+	}
+	if r.options.Config.RateLimit.Enabled {
+		api.Use(RateLimitMiddleware(r.options.Config.RateLimit))
+	}
 
 	// hotels GET endpoint
-	r.engine.GET("/hotels", handler.NewHotelsHandler().SearchHotels())
+	api.GET("/hotels", handler.NewHotelsHandler(r.options.HotelService, r.options.FlexibleSearchService, r.options.SearchPaginator).SearchHotels())
+
+	// asynchronous search job endpoints
+	if r.options.SearchJobService != nil {
+		jobsHandler := handler.NewSearchJobsHandler(r.options.SearchJobService)
+		api.POST("/hotels/search/jobs", jobsHandler.Submit())
+		api.GET("/hotels/search/jobs/:id", jobsHandler.Job())
+	}
+
+	// hotel content GET endpoint
+	if r.options.ContentService != nil {
+		api.GET("/hotels/:id/content", handler.NewContentHandler(r.options.ContentService).HotelContent())
+	}
+
+	// currency GET endpoints
+	if r.options.CurrencyService != nil {
+		currenciesHandler := handler.NewCurrenciesHandler(r.options.CurrencyService)
+		api.GET("/currencies", currenciesHandler.List())
+		api.GET("/currencies/convert", currenciesHandler.Convert())
+		api.GET("/currencies/rates", currenciesHandler.Rates())
+		api.GET("/currencies/rates/gaps", currenciesHandler.RateGaps())
+	}
 
 	return r.engine
 }
 
-func NewRouter() *Router {
+func NewRouter(options Options) *Router {
 	return &Router{
-		engine: gin.Default(),
+		engine:  newEngine(),
+		options: options,
 	}
 }
+
+// DefaultMiddleware returns the request ID, tracing and access log middleware in the order they must run
+func DefaultMiddleware() []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		logging.RequestIDMiddleware(),
+		telemetry.GinMiddleware(),
+		logging.AccessLogMiddleware(),
+	}
+}
+
+// newEngine creates a gin engine without the default text logger; requests are logged by logging.AccessLogMiddleware
+func newEngine() *gin.Engine {
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, err any) {
+		slog.ErrorContext(c.Request.Context(), "panic recovered", slog.Any("error", err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+			"error": "internal server error",
+		})
+	}))
+
+	return engine
+}
@@ -0,0 +1,281 @@
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	handlermocks "github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/handler/mocks"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/health"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	servicemocks "github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service/mocks"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRouter(hotelService service.HotelService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return NewRouter(Options{
+		Config:                config.Default(),
+		HotelService:          hotelService,
+		FlexibleSearchService: service.NewFlexibleSearchService(hotelService, config.Default().Search),
+		SearchPaginator:       service.NewSearchPaginator(config.Default().Search),
+		HealthChecker:         health.NewChecker(nil, nil),
+		Middleware:            DefaultMiddleware(),
+	}).Setup()
+}
+
+func hotelsQuery(hotelIds string) string {
+	today := time.Now()
+	return fmt.Sprintf(
+		"/hotels?hotelIds=%s&checkin=%s&checkout=%s&occupancies=[{\"rooms\":1,\"adults\":2}]&currency=EUR",
+		hotelIds,
+		today.AddDate(0, 0, 1).Format("2006-01-02"),
+		today.AddDate(0, 0, 2).Format("2006-01-02"),
+	)
+}
+
+func TestRouter_Health(t *testing.T) {
+	router := setupRouter(&handlermocks.MockHotelService{})
+
+	for _, path := range []string{"/health", "/health/live", "/health/ready"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.NotEmpty(t, w.Header().Get(util.HeaderRequestID), path)
+	}
+}
+
+func TestRouter_Hotels(t *testing.T) {
+	tests := []struct {
+		name          string
+		hotelService  service.HotelService
+		hotelIds      string
+		expectedCode  int
+		expectedLen   int
+		expectedError string
+	}{
+		{
+			name:         "Mock service",
+			hotelService: &handlermocks.MockHotelService{},
+			hotelIds:     "1234",
+			expectedCode: http.StatusOK,
+			expectedLen:  1,
+		},
+		{
+			name:          "Mock service error",
+			hotelService:  &handlermocks.MockHotelService{},
+			hotelIds:      "9999",
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: "service error",
+		},
+		{
+			name:         "Real service with mock supplier",
+			hotelService: service.NewHotelService(&servicemocks.MockHotelBedsClient{}, &servicemocks.MockCurrencyService{}, nil, ""),
+			hotelIds:     "1234,5678",
+			expectedCode: http.StatusOK,
+			expectedLen:  2,
+		},
+		{
+			name:          "Real service with failing supplier",
+			hotelService:  service.NewHotelService(&servicemocks.MockHotelBedsClient{ShouldError: true}, &servicemocks.MockCurrencyService{}, nil, ""),
+			hotelIds:      "1234",
+			expectedCode:  http.StatusInternalServerError,
+			expectedError: "client error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := setupRouter(tt.hotelService)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", hotelsQuery(tt.hotelIds), nil)
+			req.Header.Set(util.HeaderSupplierConfig, "test-supplier-config")
+			req.Header.Set(util.HeaderRequestID, "router-test")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, "router-test", w.Header().Get(util.HeaderRequestID))
+
+			if tt.expectedError != "" {
+				var response map[string]string
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Contains(t, response["error"], tt.expectedError)
+				return
+			}
+
+			var response dto.HotelPriceResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Len(t, response.Data, tt.expectedLen)
+			assert.Equal(t, "1234", response.Data[0].HotelID)
+		})
+	}
+}
+
+func TestRouter_NotFound(t *testing.T) {
+	router := setupRouter(&handlermocks.MockHotelService{})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/unknown", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouter_Content(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		contentService service.ContentService
+		expectedCode   int
+	}{
+		{
+			name:           "Content service configured",
+			contentService: &handlermocks.MockContentService{},
+			expectedCode:   http.StatusOK,
+		},
+		{
+			name:         "No content service",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(Options{
+				Config:         config.Default(),
+				HotelService:   &handlermocks.MockHotelService{},
+				ContentService: tt.contentService,
+				HealthChecker:  health.NewChecker(nil, nil),
+			}).Setup()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/hotels/1234/content", nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
+
+func TestRouter_Currencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name            string
+		currencyService service.CurrencyService
+		expectedCode    int
+	}{
+		{
+			name:            "Currency service configured",
+			currencyService: &servicemocks.MockCurrencyService{},
+			expectedCode:    http.StatusOK,
+		},
+		{
+			name:         "No currency service",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(Options{
+				Config:          config.Default(),
+				HotelService:    &handlermocks.MockHotelService{},
+				CurrencyService: tt.currencyService,
+				HealthChecker:   health.NewChecker(nil, nil),
+			}).Setup()
+
+			for _, path := range []string{"/currencies", "/currencies/convert?amount=10&from=EUR&to=USD", "/currencies/rates?base=EUR", "/currencies/rates/gaps?from=2024-01-01&to=2024-01-31"} {
+				w := httptest.NewRecorder()
+				req, _ := http.NewRequest("GET", path, nil)
+				router.ServeHTTP(w, req)
+
+				assert.Equal(t, tt.expectedCode, w.Code, path)
+			}
+		})
+	}
+}
+
+func TestRouter_SearchJobs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name             string
+		searchJobService service.SearchJobService
+		expectedCode     int
+	}{
+		{
+			name:             "Search job service configured",
+			searchJobService: &handlermocks.MockSearchJobService{},
+			expectedCode:     http.StatusOK,
+		},
+		{
+			name:         "No search job service",
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := NewRouter(Options{
+				Config:           config.Default(),
+				HotelService:     &handlermocks.MockHotelService{},
+				ContentService:   &handlermocks.MockContentService{},
+				SearchJobService: tt.searchJobService,
+				HealthChecker:    health.NewChecker(nil, nil),
+			}).Setup()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/hotels/search/jobs/job-1", nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.expectedCode, w.Code)
+
+			// The content route shares the /hotels/:id prefix
+			w = httptest.NewRecorder()
+			req, _ = http.NewRequest("GET", "/hotels/1234/content", nil)
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+		})
+	}
+}
+
+func TestRouter_Authentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := NewRouter(Options{
+		Config:        config.Default(),
+		HotelService:  &handlermocks.MockHotelService{},
+		HealthChecker: health.NewChecker(nil, nil),
+		KeyStore:      mockKeyStore{"valid": nil},
+		Middleware:    DefaultMiddleware(),
+	}).Setup()
+
+	// Health endpoints stay open
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/health/live", nil)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", hotelsQuery("1234"), nil)
+	req.Header.Set(util.HeaderSupplierConfig, "test-supplier-config")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", hotelsQuery("1234"), nil)
+	req.Header.Set(util.HeaderSupplierConfig, "test-supplier-config")
+	req.Header.Set(util.HeaderLiteAPIKey, "valid")
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
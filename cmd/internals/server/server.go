@@ -0,0 +1,103 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+)
+
+// ShutdownHook is run once the server has stopped accepting requests, e.g. to flush caches or exporters
+type ShutdownHook func(context.Context) error
+
+type Server struct {
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
+	hookTimeout     time.Duration
+	hooks           []ShutdownHook
+}
+
+func New(cfg config.ServerConfig, handler http.Handler) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              fmt.Sprintf(":%s", cfg.Port),
+			Handler:           handler,
+			ReadTimeout:       cfg.ReadTimeout.Duration,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout.Duration,
+			WriteTimeout:      cfg.WriteTimeout.Duration,
+			IdleTimeout:       cfg.IdleTimeout.Duration,
+			MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		},
+		shutdownTimeout: cfg.ShutdownTimeout.Duration,
+		hookTimeout:     cfg.ShutdownHookTimeout.Duration,
+	}
+}
+
+// OnShutdown registers a hook; hooks run in registration order after in-flight requests have drained,
+// each with its own hook timeout
+func (s *Server) OnShutdown(hook ShutdownHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// ListenAndServe listens on the configured address and serves until ctx is cancelled
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %v: %w", s.httpServer.Addr, err)
+	}
+
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts connections on ln until ctx is cancelled, then drains in-flight
+// requests within the shutdown timeout and runs the shutdown hooks
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", slog.String("addr", ln.Addr().String()))
+		serveErr <- s.httpServer.Serve(ln)
+	}()
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server stopped unexpectedly: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down server", slog.Duration("grace_period", s.shutdownTimeout))
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to drain connections: %w", err))
+	}
+
+	// The drain may have used the whole grace period, so hooks never reuse its context
+	for _, hook := range s.hooks {
+		if err := s.runHook(hook); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	slog.Info("server stopped")
+
+	return errors.Join(errs...)
+}
+
+// runHook runs hook with a context bounded by the hook timeout
+func (s *Server) runHook(hook ShutdownHook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.hookTimeout)
+	defer cancel()
+
+	return hook(ctx)
+}
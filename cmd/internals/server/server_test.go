@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	cfg := config.Default().Server
+	srv := New(cfg, http.NotFoundHandler())
+
+	assert.Equal(t, ":8080", srv.httpServer.Addr)
+	assert.Equal(t, cfg.ReadTimeout.Duration, srv.httpServer.ReadTimeout)
+	assert.Equal(t, cfg.ReadHeaderTimeout.Duration, srv.httpServer.ReadHeaderTimeout)
+	assert.Equal(t, cfg.WriteTimeout.Duration, srv.httpServer.WriteTimeout)
+	assert.Equal(t, cfg.IdleTimeout.Duration, srv.httpServer.IdleTimeout)
+	assert.Equal(t, cfg.MaxHeaderBytes, srv.httpServer.MaxHeaderBytes)
+	assert.Equal(t, cfg.ShutdownTimeout.Duration, srv.shutdownTimeout)
+	assert.Equal(t, cfg.ShutdownHookTimeout.Duration, srv.hookTimeout)
+}
+
+func TestServe_GracefulShutdown(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	cfg := config.Default().Server
+	cfg.ShutdownTimeout = config.Duration{Duration: time.Second}
+	srv := New(cfg, handler)
+
+	var hooks []string
+	srv.OnShutdown(func(context.Context) error {
+		hooks = append(hooks, "content")
+		return nil
+	})
+	srv.OnShutdown(func(context.Context) error {
+		hooks = append(hooks, "tracing")
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	// Cancel while the request is in flight; it must still complete
+	responses := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			responses <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		responses <- string(body)
+	}()
+
+	<-started
+	cancel()
+
+	assert.Equal(t, "done", <-responses)
+	assert.NoError(t, <-served)
+	assert.Equal(t, []string{"content", "tracing"}, hooks)
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	defer close(release)
+
+	cfg := config.Default().Server
+	cfg.ShutdownTimeout = config.Duration{Duration: 50 * time.Millisecond}
+	srv := New(cfg, handler)
+
+	// The drain uses up the grace period; the hook must still get a live context
+	var hookErr error
+	srv.OnShutdown(func(ctx context.Context) error {
+		hookErr = ctx.Err()
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	go http.Get("http://" + ln.Addr().String())
+
+	<-started
+	cancel()
+
+	err = <-served
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to drain connections")
+	assert.NoError(t, hookErr)
+}
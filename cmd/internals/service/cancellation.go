@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+	_ "time/tzdata" // hotel time zones must resolve on hosts without a zoneinfo database
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+// destinationTimezones maps Hotelbeds destination codes to the time zone of their hotels.
+// Policies of other destinations keep the offset sent by the supplier.
+var destinationTimezones = map[string]string{
+	"AMS": "Europe/Amsterdam",
+	"ATH": "Europe/Athens",
+	"BCN": "Europe/Madrid",
+	"BER": "Europe/Berlin",
+	"BKK": "Asia/Bangkok",
+	"CUN": "America/Cancun",
+	"DXB": "Asia/Dubai",
+	"IBZ": "Europe/Madrid",
+	"LIS": "Europe/Lisbon",
+	"LON": "Europe/London",
+	"MAD": "Europe/Madrid",
+	"MIA": "America/New_York",
+	"NYC": "America/New_York",
+	"PAR": "Europe/Paris",
+	"PMI": "Europe/Madrid",
+	"ROM": "Europe/Rome",
+	"SIN": "Asia/Singapore",
+	"TFS": "Atlantic/Canary",
+	"TYO": "Asia/Tokyo",
+}
+
+// hotelLocation returns the hotel's time zone, or nil when it is not known
+func hotelLocation(destinationCode string) *time.Location {
+	name, ok := destinationTimezones[destinationCode]
+	if !ok {
+		return nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil
+	}
+
+	return loc
+}
+
+// isRefundable reports whether the rate can still be cancelled for free at now
+func isRefundable(rate dto.Rate, now time.Time) bool {
+	if rate.RateClass == dto.RateClassNonRefundable {
+		return false
+	}
+
+	for _, policy := range rate.CancellationPolicies {
+		from, err := time.Parse(time.RFC3339, policy.From)
+		if err != nil || !from.After(now) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cancellationTerms normalizes the rate's Hotelbeds penalties into the liteAPI policy, converting the
+// amounts like the price and the times into the hotel's time zone
+func (h *HotelServiceImpl) cancellationTerms(ctx context.Context, rate dto.Rate, hotel dto.Hotel, currency string, now time.Time) (*dto.CancellationTerms, error) {
+	if len(rate.CancellationPolicies) == 0 && rate.RateClass != dto.RateClassNonRefundable {
+		return nil, nil
+	}
+
+	type tier struct {
+		from   time.Time
+		amount float64
+	}
+
+	opts := h.currService.Options(hotel.Currency, currency)
+	tiers := make([]tier, 0, len(rate.CancellationPolicies))
+	for _, policy := range rate.CancellationPolicies {
+		from, err := time.Parse(time.RFC3339, policy.From)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cancellation date: %v", policy.From)
+		}
+
+		amount, err := strconv.ParseFloat(policy.Amount, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse cancellation amount: %v", policy.Amount)
+		}
+
+		amount, err = h.sellingPrice(ctx, amount, hotel.Currency, currency)
+		if err != nil {
+			return nil, err
+		}
+
+		tiers = append(tiers, tier{from: from, amount: roundPrice(amount, currency, opts)})
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].from.Before(tiers[j].from) })
+
+	loc := hotelLocation(hotel.DestinationCode)
+	format := func(t time.Time) string {
+		if loc != nil {
+			t = t.In(loc)
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	terms := &dto.CancellationTerms{Refundable: isRefundable(rate, now)}
+	for _, t := range tiers {
+		terms.Penalties = append(terms.Penalties, dto.CancellationPenalty{From: format(t.from), Amount: t.amount})
+	}
+
+	if terms.Refundable && len(tiers) > 0 {
+		terms.RefundableUntil = format(tiers[0].from)
+	}
+
+	return terms, nil
+}
@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/idmap"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrContentNotFound is returned for a hotel the Content API does not know
+var ErrContentNotFound = errors.New("hotel content not found")
+
+// searchImages caps the images added to each search result; the content endpoint returns all of them
+const searchImages = 3
+
+// ContentStore keeps the static content of hotels by supplier code
+type ContentStore interface {
+	Get(code int) (dto.HotelContent, bool)
+	Put(contents ...dto.HotelContent)
+	// Save persists the store; it is a no-op for a store kept in memory only
+	Save() error
+	Len() int
+}
+
+type ContentStoreImpl struct {
+	mu       sync.RWMutex
+	contents map[int]dto.HotelContent
+	file     string
+}
+
+// NewContentStore creates the content store, loading file when it exists; an empty file keeps the
+// content in memory only
+func NewContentStore(file string) (ContentStore, error) {
+	store := &ContentStoreImpl{
+		contents: make(map[int]dto.HotelContent),
+		file:     file,
+	}
+	if file == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content store: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &store.contents); err != nil {
+		return nil, fmt.Errorf("failed to parse content store %v: %w", file, err)
+	}
+
+	return store, nil
+}
+
+func (s *ContentStoreImpl) Get(code int) (dto.HotelContent, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	content, ok := s.contents[code]
+
+	return content, ok
+}
+
+func (s *ContentStoreImpl) Put(contents ...dto.HotelContent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, content := range contents {
+		code, err := strconv.Atoi(content.HotelID)
+		if err != nil {
+			continue
+		}
+		s.contents[code] = content
+	}
+}
+
+// Save writes the store to a temporary file and renames it, so a crash never leaves a truncated store
+func (s *ContentStoreImpl) Save() error {
+	if s.file == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	data, err := json.Marshal(s.contents)
+	s.mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("failed to encode content store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.file), filepath.Base(s.file)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write content store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write content store: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write content store: %w", err)
+	}
+
+	if err = os.Rename(tmp.Name(), s.file); err != nil {
+		return fmt.Errorf("failed to write content store: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ContentStoreImpl) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.contents)
+}
+
+// ContentService serves hotel static content from the store, falling back to the Content API
+type ContentService interface {
+	HotelContent(ctx context.Context, hotelID string) (dto.HotelContent, error)
+	// Sync fetches the content of the whole portfolio into the store
+	Sync(ctx context.Context) error
+}
+
+type ContentServiceImpl struct {
+	client       client.ContentClient
+	store        ContentStore
+	mapper       idmap.Mapper
+	pageSize     int
+	imageBaseURL string
+}
+
+// NewContentService creates the content service; a nil mapper uses the Hotelbeds codes as liteAPI hotel IDs
+func NewContentService(client client.ContentClient, store ContentStore, mapper idmap.Mapper, cfg config.ContentConfig) ContentService {
+	if mapper == nil {
+		mapper = idmap.Identity()
+	}
+
+	return &ContentServiceImpl{
+		client:       client,
+		store:        store,
+		mapper:       mapper,
+		pageSize:     cfg.PageSize,
+		imageBaseURL: cfg.ImageBaseURL,
+	}
+}
+
+// HotelContent returns the stored content of a hotel; a hotel not synced yet is fetched and stored.
+// The store is keyed by Hotelbeds code, so the content is returned under the liteAPI hotel ID.
+func (s *ContentServiceImpl) HotelContent(ctx context.Context, hotelID string) (content dto.HotelContent, err error) {
+	code, ok := supplierCode(s.mapper, hotelID)
+	if !ok {
+		return content, fmt.Errorf("%w: %v", ErrContentNotFound, hotelID)
+	}
+
+	if content, ok := s.store.Get(code); ok {
+		content.HotelID = hotelID
+		return content, nil
+	}
+
+	ctx, span := telemetry.StartSpan(ctx, "ContentService.HotelContent")
+	span.SetAttributes(attribute.String("hotel.id", hotelID))
+	defer func() {
+		if err != nil && !errors.Is(err, ErrContentNotFound) {
+			telemetry.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	page, err := s.fetch(ctx, []int{code}, 1, 1)
+	if err != nil {
+		return content, err
+	}
+
+	for _, hotel := range page.Hotels {
+		if hotel.Code == code {
+			content = s.hotelContent(hotel)
+			s.store.Put(content)
+			content.HotelID = hotelID
+			return content, nil
+		}
+	}
+
+	return content, fmt.Errorf("%w: %v", ErrContentNotFound, hotelID)
+}
+
+func (s *ContentServiceImpl) Sync(ctx context.Context) (err error) {
+	ctx, span := telemetry.StartSpan(ctx, "ContentService.Sync")
+	defer func() {
+		if err != nil {
+			telemetry.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	start := time.Now()
+	synced := 0
+
+	// The Content API pages with inclusive 1-based positions; total is only known after the first page
+	for from := 1; ; from += s.pageSize {
+		page, err := s.fetch(ctx, nil, from, from+s.pageSize-1)
+		if err != nil {
+			return fmt.Errorf("content sync stopped after %d hotels: %w", synced, err)
+		}
+
+		contents := make([]dto.HotelContent, len(page.Hotels))
+		for i, hotel := range page.Hotels {
+			contents[i] = s.hotelContent(hotel)
+		}
+		s.store.Put(contents...)
+		synced += len(contents)
+
+		if len(page.Hotels) == 0 || from+s.pageSize > page.Total {
+			break
+		}
+	}
+
+	span.SetAttributes(attribute.Int("hotels.synced", synced))
+	slog.InfoContext(ctx, "hotel content synced", slog.Int("hotels", synced), slog.Duration("took", time.Since(start)))
+
+	return s.store.Save()
+}
+
+func (s *ContentServiceImpl) fetch(ctx context.Context, codes []int, from, to int) (dto.HotelbedsContentResponse, error) {
+	var page dto.HotelbedsContentResponse
+
+	response, err := s.client.Hotels(ctx, codes, from, to)
+	if err != nil {
+		return page, err
+	}
+
+	if err = json.Unmarshal(response, &page); err != nil {
+		return page, fmt.Errorf("failed to parse content response: %w", err)
+	}
+
+	return page, nil
+}
+
+// hotelContent maps a Content API hotel to its liteAPI content, listing images in display order
+func (s *ContentServiceImpl) hotelContent(hotel dto.ContentHotel) dto.HotelContent {
+	content := dto.HotelContent{
+		HotelID:         strconv.Itoa(hotel.Code),
+		Name:            hotel.Name.Content,
+		Description:     hotel.Description.Content,
+		Category:        hotel.CategoryCode,
+		Stars:           dto.StarsFromCategory(hotel.CategoryCode),
+		CountryCode:     hotel.CountryCode,
+		DestinationCode: hotel.DestinationCode,
+		Address:         hotel.Address.Content,
+		City:            hotel.City.Content,
+	}
+
+	if hotel.Coordinates != nil {
+		content.Coordinates = &dto.ContentLocation{
+			Latitude:  hotel.Coordinates.Latitude,
+			Longitude: hotel.Coordinates.Longitude,
+		}
+	}
+
+	for _, facility := range hotel.Facilities {
+		content.Facilities = append(content.Facilities, dto.HotelFacility{
+			Code:        facility.FacilityCode,
+			Group:       facility.FacilityGroupCode,
+			Description: facility.Description.Content,
+		})
+	}
+
+	images := slices.Clone(hotel.Images)
+	slices.SortStableFunc(images, func(a, b dto.ContentImage) int {
+		if a.VisualOrder != b.VisualOrder {
+			return a.VisualOrder - b.VisualOrder
+		}
+		return a.Order - b.Order
+	})
+	for _, image := range images {
+		content.Images = append(content.Images, dto.HotelImage{
+			URL:  s.imageBaseURL + image.Path,
+			Type: image.ImageTypeCode,
+		})
+	}
+
+	return content
+}
+
+// RunContentSync syncs the content at once and then every interval until ctx is done; a failed sync is
+// logged and retried at the next tick, keeping the content already stored
+func RunContentSync(ctx context.Context, contentService ContentService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := contentService.Sync(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "hotel content sync failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ContentEnrichedHotelService adds the stored name, stars and images to the hotels found by a search.
+// It only reads the store, so a search never waits on the Content API.
+type ContentEnrichedHotelService struct {
+	hotelService HotelService
+	store        ContentStore
+	mapper       idmap.Mapper
+}
+
+// NewContentEnrichedHotelService wraps hotelService; a nil mapper uses the Hotelbeds codes as liteAPI hotel IDs
+func NewContentEnrichedHotelService(hotelService HotelService, store ContentStore, mapper idmap.Mapper) HotelService {
+	if mapper == nil {
+		mapper = idmap.Identity()
+	}
+
+	return &ContentEnrichedHotelService{
+		hotelService: hotelService,
+		store:        store,
+		mapper:       mapper,
+	}
+}
+
+func (s *ContentEnrichedHotelService) SearchHotels(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+	response, err := s.hotelService.SearchHotels(ctx, params)
+	if err != nil {
+		return response, err
+	}
+
+	for i, price := range response.HotelPrices {
+		code, ok := supplierCode(s.mapper, price.HotelID)
+		if !ok {
+			continue
+		}
+
+		content, ok := s.store.Get(code)
+		if !ok {
+			continue
+		}
+
+		// The supplier name is kept when there is one
+		if price.Name == "" {
+			response.HotelPrices[i].Name = content.Name
+		}
+		response.HotelPrices[i].Stars = content.Stars
+		response.HotelPrices[i].Images = content.Images[:min(len(content.Images), searchImages)]
+	}
+
+	// Sorted again, as the hotels were sorted before the enriched names were known
+	sortHotelPrices(response.HotelPrices, params.Sort)
+
+	return response, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/idmap"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func contentHotels(codes ...int) []dto.ContentHotel {
+	hotels := make([]dto.ContentHotel, len(codes))
+	for i, code := range codes {
+		hotels[i] = dto.ContentHotel{
+			Code:         code,
+			Name:         dto.ContentText{Content: "Hotel " + string(rune('A'+i))},
+			CategoryCode: "4EST",
+			Coordinates:  &dto.ContentCoordinate{Latitude: 39.5696, Longitude: 2.6502},
+			Images: []dto.ContentImage{
+				{ImageTypeCode: "HAB", Path: "room.jpg", Order: 1, VisualOrder: 1},
+				{ImageTypeCode: "GEN", Path: "front.jpg", Order: 1, VisualOrder: 0},
+			},
+		}
+	}
+
+	return hotels
+}
+
+func newTestContentService(client *mocks.MockContentClient, store ContentStore, pageSize int) ContentService {
+	cfg := config.Default().Content
+	cfg.PageSize = pageSize
+	cfg.ImageBaseURL = "https://photos.example.com/"
+
+	return NewContentService(client, store, nil, cfg)
+}
+
+func TestContentService_Sync(t *testing.T) {
+	tests := []struct {
+		name          string
+		hotels        int
+		pageSize      int
+		expectedCalls int
+	}{
+		{name: "Single page", hotels: 3, pageSize: 10, expectedCalls: 1},
+		{name: "Exact pages", hotels: 4, pageSize: 2, expectedCalls: 2},
+		{name: "Partial last page", hotels: 5, pageSize: 2, expectedCalls: 3},
+		{name: "Empty portfolio", hotels: 0, pageSize: 2, expectedCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codes := make([]int, tt.hotels)
+			for i := range codes {
+				codes[i] = 1000 + i
+			}
+			client := &mocks.MockContentClient{Portfolio: contentHotels(codes...)}
+			store, _ := NewContentStore("")
+
+			err := newTestContentService(client, store, tt.pageSize).Sync(context.Background())
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCalls, client.Calls)
+			assert.Equal(t, tt.hotels, store.Len())
+		})
+	}
+}
+
+func TestContentService_SyncError(t *testing.T) {
+	store, _ := NewContentStore("")
+	store.Put(dto.HotelContent{HotelID: "1234", Name: "Kept"})
+
+	err := newTestContentService(&mocks.MockContentClient{ShouldError: true}, store, 10).Sync(context.Background())
+
+	assert.Error(t, err)
+	content, ok := store.Get(1234)
+	assert.True(t, ok, "a failed sync must keep the stored content")
+	assert.Equal(t, "Kept", content.Name)
+}
+
+func TestContentService_HotelContent(t *testing.T) {
+	client := &mocks.MockContentClient{Portfolio: contentHotels(1234)}
+	store, _ := NewContentStore("")
+	contentService := newTestContentService(client, store, 10)
+
+	content, err := contentService.HotelContent(context.Background(), "1234")
+	assert.NoError(t, err)
+	assert.Equal(t, "1234", content.HotelID)
+	assert.Equal(t, "Hotel A", content.Name)
+	assert.Equal(t, 4, content.Stars)
+	assert.Equal(t, &dto.ContentLocation{Latitude: 39.5696, Longitude: 2.6502}, content.Coordinates)
+	assert.Equal(t, []dto.HotelImage{
+		{URL: "https://photos.example.com/front.jpg", Type: "GEN"},
+		{URL: "https://photos.example.com/room.jpg", Type: "HAB"},
+	}, content.Images, "images are listed in visual order")
+
+	// The fetched content is stored, so the supplier is called once
+	_, err = contentService.HotelContent(context.Background(), "1234")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, client.Calls)
+
+	_, err = contentService.HotelContent(context.Background(), "4321")
+	assert.True(t, errors.Is(err, ErrContentNotFound))
+}
+
+func TestContentStore_Persistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "content.json")
+
+	store, err := NewContentStore(file)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, store.Len())
+
+	store.Put(dto.HotelContent{HotelID: "1234", Name: "Zafiro Palace", Stars: 5})
+	assert.NoError(t, store.Save())
+
+	reloaded, err := NewContentStore(file)
+	assert.NoError(t, err)
+	content, ok := reloaded.Get(1234)
+	assert.True(t, ok)
+	assert.Equal(t, "Zafiro Palace", content.Name)
+	assert.Equal(t, 5, content.Stars)
+}
+
+func TestContentEnrichedHotelService(t *testing.T) {
+	store, _ := NewContentStore("")
+	store.Put(dto.HotelContent{
+		HotelID: "1234",
+		Name:    "Zafiro Palace",
+		Stars:   5,
+		Images: []dto.HotelImage{
+			{URL: "1.jpg"}, {URL: "2.jpg"}, {URL: "3.jpg"}, {URL: "4.jpg"},
+		},
+	})
+
+	hotelService := &stubHotelService{prices: map[string]float64{"2030-06-01": 100}}
+	response, err := NewContentEnrichedHotelService(hotelService, store, nil).SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+		CheckIn:  "2030-06-01",
+		CheckOut: "2030-06-02",
+		HotelIDs: []string{"1234", "5678"},
+		Currency: "EUR",
+	})
+	assert.NoError(t, err)
+
+	prices := map[string]dto.HotelPrice{}
+	for _, price := range response.HotelPrices {
+		prices[price.HotelID] = price
+	}
+
+	assert.Equal(t, "Zafiro Palace", prices["1234"].Name)
+	assert.Equal(t, 5, prices["1234"].Stars)
+	assert.Len(t, prices["1234"].Images, searchImages)
+	assert.Empty(t, prices["5678"].Name, "hotels not synced are left as they are")
+	assert.Zero(t, prices["5678"].Stars)
+	assert.Empty(t, prices["5678"].Images)
+}
+
+func TestContentEnrichedHotelService_SortedByEnrichedName(t *testing.T) {
+	store, _ := NewContentStore("")
+	store.Put(dto.HotelContent{HotelID: "1", Name: "Zafiro Palace"})
+	store.Put(dto.HotelContent{HotelID: "2", Name: "Almudaina"})
+
+	// The supplier returns the hotels sorted by its own, missing, names
+	hotelService := hotelServiceFunc(func(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+		return dto.HotelSearchServiceResponse{HotelPrices: []dto.HotelPrice{
+			{HotelID: "1"}, {HotelID: "3", Name: "Belmond"}, {HotelID: "2"},
+		}}, nil
+	})
+
+	response, err := NewContentEnrichedHotelService(hotelService, store, nil).SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+		HotelIDs: []string{"1", "2", "3"},
+		Sort:     dto.SortName,
+	})
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, price := range response.HotelPrices {
+		names = append(names, price.Name)
+	}
+	assert.Equal(t, []string{"Almudaina", "Belmond", "Zafiro Palace"}, names)
+}
+
+func TestContentService_HotelContentMapped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"hotels":[{"hotelId":"lp-zafiro","suppliers":{"hotelbeds":"1234"}}]}`), 0o600))
+	mapper, err := idmap.NewFileMapper(path)
+	assert.NoError(t, err)
+
+	store, _ := NewContentStore("")
+	contentService := NewContentService(&mocks.MockContentClient{Portfolio: contentHotels(1234)}, store, mapper, config.Default().Content)
+
+	content, err := contentService.HotelContent(context.Background(), "lp-zafiro")
+	assert.NoError(t, err)
+	assert.Equal(t, "lp-zafiro", content.HotelID, "content is returned under the liteAPI hotel id")
+
+	_, err = contentService.HotelContent(context.Background(), "1234")
+	assert.True(t, errors.Is(err, ErrContentNotFound), "supplier codes are not hotel ids")
+}
@@ -1,34 +1,78 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/Rhymond/go-money"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
 )
 
+// ErrUnknownCurrency is returned for a code missing from the currency registry
+var ErrUnknownCurrency = errors.New("failed to find Currency by code")
+
 type CurrencyService interface {
 	Convert(amount float64, sourceCurr, targetCurr string) (float64, error)
+	// ConvertWithOptions converts like Convert, adding the spread and rounding the result as opts tell
+	ConvertWithOptions(amount float64, sourceCurr, targetCurr string, opts dto.ConversionOptions) (dto.Conversion, error)
+	// Options returns the configured FX policy of the currency pair
+	Options(sourceCurr, targetCurr string) dto.ConversionOptions
+	// RatesAsOf returns when the historical rates were last loaded, or the zero time for the static rate table
+	RatesAsOf() time.Time
+	// Currencies lists the supported currencies by code
+	Currencies() []dto.Currency
+	// Rates returns the rate of one unit of base in every supported currency
+	Rates(base string) (map[string]float64, error)
+	// ConvertCurrent converts at the plain current rate, the amount rounded to the minor units of the target
+	// currency with the configured rounding; AsOf is when the rates were last refreshed
+	ConvertCurrent(amount float64, sourceCurr, targetCurr string) (dto.Conversion, error)
+	// ConvertAt converts at the rate valid on the date of at, the latest one known on or before it, rounded
+	// like ConvertCurrent
+	ConvertAt(amount float64, sourceCurr, targetCurr string, at time.Time) (dto.Conversion, error)
+	// RateGaps reports the days between from and to without historical rates
+	RateGaps(from, to time.Time) dto.RateGapReport
 }
 
 type CurrencyServiceImpl struct {
+	policy  config.CurrencyConfig
+	history RateStore
 }
 
-func NewCurrencyService() CurrencyService {
-	return &CurrencyServiceImpl{}
+// NewCurrencyService creates the currency service; the zero policy converts without spread or rounding and
+// a nil history converts past dates with the current rate table
+func NewCurrencyService(policy config.CurrencyConfig, history RateStore) CurrencyService {
+	return &CurrencyServiceImpl{
+		policy:  policy,
+		history: history,
+	}
 }
 
-// Convert converts the currency from source to target currency
+// Convert converts the currency from source to target currency at the latest historical rate of the
+// pair, or with the static rate table without a history. Like ConvertAt, a pair the history does not
+// quote fails with ErrRateNotFound rather than mixing in static rates.
 func (c *CurrencyServiceImpl) Convert(amount float64, sourceCurr, targetCurr string) (float64, error) {
 	var val float64 = 0
-	source := money.GetCurrency(sourceCurr)
-	target := money.GetCurrency(targetCurr)
+	source, err := c.lookup(sourceCurr)
+	if err != nil {
+		return val, err
+	}
 
-	if source == nil {
-		return val, fmt.Errorf("failed to find Currency by code: %v", sourceCurr)
+	target, err := c.lookup(targetCurr)
+	if err != nil {
+		return val, err
 	}
 
-	if target == nil {
-		return val, fmt.Errorf("failed to find Currency by code: %v", targetCurr)
+	if c.history != nil {
+		rate, _, err := c.history.Rate(source.Code, target.Code, time.Now())
+		if err != nil {
+			return val, err
+		}
+
+		return amount * rate, nil
 	}
 
 	val = c.globalCurrencyConverter(amount, source, target)
@@ -36,7 +80,172 @@ func (c *CurrencyServiceImpl) Convert(amount float64, sourceCurr, targetCurr str
 	return val, nil
 }
 
+func (c *CurrencyServiceImpl) ConvertWithOptions(amount float64, sourceCurr, targetCurr string, opts dto.ConversionOptions) (dto.Conversion, error) {
+	var conversion dto.Conversion
+
+	rate, err := c.Convert(1, sourceCurr, targetCurr)
+	if err != nil {
+		return conversion, err
+	}
+
+	conversion.Rate = rate * (1 + float64(opts.SpreadBps)/10000)
+	conversion.Amount = amount * conversion.Rate
+	if opts.Rounding != "" {
+		conversion.Amount = roundMinorUnits(conversion.Amount, money.GetCurrency(targetCurr).Fraction, opts.Rounding)
+	}
+
+	return conversion, nil
+}
+
+// Options resolves the spread of the pair, falling back to the default spread; an amount kept in its
+// own currency has no spread. Codes are case insensitive, like in the currency registry.
+func (c *CurrencyServiceImpl) Options(sourceCurr, targetCurr string) dto.ConversionOptions {
+	opts := dto.ConversionOptions{
+		Rounding:    c.policy.Rounding,
+		DisplayOnly: c.policy.ChargeInSupplierCurrency,
+	}
+
+	sourceCurr, targetCurr = strings.ToUpper(sourceCurr), strings.ToUpper(targetCurr)
+	if sourceCurr == targetCurr {
+		return opts
+	}
+
+	opts.SpreadBps = c.policy.SpreadBps
+	if spread, ok := c.policy.Spreads[sourceCurr+"/"+targetCurr]; ok {
+		opts.SpreadBps = spread
+	}
+
+	return opts
+}
+
+// roundMinorUnits rounds amount to the given decimals with the rounding mode. The scaled amount is
+// first cleaned of float noise, so 10.35 rounded down stays 10.35.
+func roundMinorUnits(amount float64, decimals int, mode string) float64 {
+	scale := math.Pow10(decimals)
+	scaled := math.Round(amount*scale*1e6) / 1e6
+
+	switch mode {
+	case config.RoundingUp:
+		scaled = math.Ceil(scaled)
+	case config.RoundingDown:
+		scaled = math.Floor(scaled)
+	default:
+		scaled = math.RoundToEven(scaled)
+	}
+
+	return scaled / scale
+}
+
+func (c *CurrencyServiceImpl) ConvertCurrent(amount float64, sourceCurr, targetCurr string) (dto.Conversion, error) {
+	rate, err := c.Convert(1, sourceCurr, targetCurr)
+	if err != nil {
+		return dto.Conversion{}, err
+	}
+
+	return c.plainConversion(amount, rate, targetCurr, c.RatesAsOf()), nil
+}
+
+func (c *CurrencyServiceImpl) ConvertAt(amount float64, sourceCurr, targetCurr string, at time.Time) (dto.Conversion, error) {
+	if c.history == nil {
+		rate, err := c.Convert(1, sourceCurr, targetCurr)
+		if err != nil {
+			return dto.Conversion{}, err
+		}
+
+		return c.plainConversion(amount, rate, targetCurr, dayOf(at)), nil
+	}
+
+	rate, day, err := c.history.Rate(sourceCurr, targetCurr, at)
+	if err != nil {
+		return dto.Conversion{}, err
+	}
+
+	return c.plainConversion(amount, rate, targetCurr, day), nil
+}
+
+// plainConversion converts amount at rate, without spread, rounded to the minor units of the target currency
+// with the configured rounding, half-even under the zero policy
+func (c *CurrencyServiceImpl) plainConversion(amount, rate float64, targetCurr string, asOf time.Time) dto.Conversion {
+	return dto.Conversion{
+		Amount: roundMinorUnits(amount*rate, money.GetCurrency(targetCurr).Fraction, c.policy.Rounding),
+		Rate:   rate,
+		AsOf:   asOf,
+	}
+}
+
+// RateGaps reports no gaps without a history, as the current rate table then applies to every day
+func (c *CurrencyServiceImpl) RateGaps(from, to time.Time) dto.RateGapReport {
+	if c.history == nil {
+		from, to = dayOf(from), dayOf(to)
+		return dto.RateGapReport{
+			From:        from.Format(dateLayout),
+			To:          to.Format(dateLayout),
+			Days:        int(to.Sub(from).Hours()/24) + 1,
+			MissingDays: []string{},
+		}
+	}
+
+	return c.history.Gaps(from, to)
+}
+
+// lookup finds a currency in the go-money registry
+func (c *CurrencyServiceImpl) lookup(code string) (*money.Currency, error) {
+	currency := money.GetCurrency(code)
+	if currency == nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnknownCurrency, code)
+	}
+
+	return currency, nil
+}
+
 // globalCurrencyConverter converts the currencies: Currently has a 1:1 currency conversion ratio
 func (c *CurrencyServiceImpl) globalCurrencyConverter(amount float64, source, target *money.Currency) float64 {
 	return amount
 }
+
+// RatesAsOf is when the historical rates were last loaded; nothing refreshes the static 1:1 table, so it
+// has no date
+func (c *CurrencyServiceImpl) RatesAsOf() time.Time {
+	if c.history == nil {
+		return time.Time{}
+	}
+
+	return c.history.LoadedAt()
+}
+
+func (c *CurrencyServiceImpl) Currencies() []dto.Currency {
+	currencies := make([]dto.Currency, 0, len(currencyCodes))
+	for _, code := range currencyCodes {
+		currency, err := c.lookup(code)
+		if err != nil {
+			continue
+		}
+
+		currencies = append(currencies, dto.Currency{
+			Code:        currency.Code,
+			NumericCode: currency.NumericCode,
+			Decimals:    currency.Fraction,
+			Symbol:      currency.Grapheme,
+		})
+	}
+
+	return currencies
+}
+
+// Rates converts one unit of base into every supported currency, so the table matches Convert exactly
+func (c *CurrencyServiceImpl) Rates(base string) (map[string]float64, error) {
+	if _, err := c.lookup(base); err != nil {
+		return nil, err
+	}
+
+	rates := make(map[string]float64, len(currencyCodes))
+	for _, code := range currencyCodes {
+		rate, err := c.Convert(1, base, code)
+		if err != nil {
+			continue
+		}
+		rates[code] = rate
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,25 @@
+package service
+
+import "github.com/Rhymond/go-money"
+
+// currencyCodes lists the ISO 4217 codes of the go-money registry, which does not export its table
+var currencyCodes = []string{
+	money.AED, money.AFN, money.ALL, money.AMD, money.ANG, money.AOA, money.ARS, money.AUD, money.AWG, money.AZN,
+	money.BAM, money.BBD, money.BDT, money.BGN, money.BHD, money.BIF, money.BMD, money.BND, money.BOB, money.BRL,
+	money.BSD, money.BTN, money.BWP, money.BYN, money.BYR, money.BZD, money.CAD, money.CDF, money.CHF, money.CLF,
+	money.CLP, money.CNY, money.COP, money.CRC, money.CUC, money.CUP, money.CVE, money.CZK, money.DJF, money.DKK,
+	money.DOP, money.DZD, money.EEK, money.EGP, money.ERN, money.ETB, money.EUR, money.FJD, money.FKP, money.GBP,
+	money.GEL, money.GGP, money.GHC, money.GHS, money.GIP, money.GMD, money.GNF, money.GTQ, money.GYD, money.HKD,
+	money.HNL, money.HRK, money.HTG, money.HUF, money.IDR, money.ILS, money.IMP, money.INR, money.IQD, money.IRR,
+	money.ISK, money.JEP, money.JMD, money.JOD, money.JPY, money.KES, money.KGS, money.KHR, money.KMF, money.KPW,
+	money.KRW, money.KWD, money.KYD, money.KZT, money.LAK, money.LBP, money.LKR, money.LRD, money.LSL, money.LTL,
+	money.LVL, money.LYD, money.MAD, money.MDL, money.MGA, money.MKD, money.MMK, money.MNT, money.MOP, money.MUR,
+	money.MRU, money.MVR, money.MWK, money.MXN, money.MYR, money.MZN, money.NAD, money.NGN, money.NIO, money.NOK,
+	money.NPR, money.NZD, money.OMR, money.PAB, money.PEN, money.PGK, money.PHP, money.PKR, money.PLN, money.PYG,
+	money.QAR, money.RON, money.RSD, money.RUB, money.RUR, money.RWF, money.SAR, money.SBD, money.SCR, money.SDG,
+	money.SEK, money.SGD, money.SHP, money.SKK, money.SLE, money.SLL, money.SOS, money.SRD, money.SSP, money.STD,
+	money.STN, money.SVC, money.SYP, money.SZL, money.THB, money.TJS, money.TMT, money.TND, money.TOP, money.TRL,
+	money.TRY, money.TTD, money.TWD, money.TZS, money.UAH, money.UGX, money.USD, money.UYU, money.UZS, money.VEF,
+	money.VES, money.VND, money.VUV, money.WST, money.XAF, money.XAG, money.XAU, money.XCD, money.XDR, money.XOF,
+	money.XPF, money.YER, money.ZAR, money.ZMW, money.ZWD, money.ZWL,
+}
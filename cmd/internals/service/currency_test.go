@@ -1,19 +1,23 @@
 package service
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/Rhymond/go-money"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewCurrencyService(t *testing.T) {
-	currencyService := NewCurrencyService()
+	currencyService := NewCurrencyService(config.CurrencyConfig{}, nil)
 	assert.NotNil(t, currencyService, "CurrencyService instance should not be nil")
 }
 
 func TestConvert(t *testing.T) {
-	currencyService := NewCurrencyService()
+	currencyService := NewCurrencyService(config.CurrencyConfig{}, nil)
 
 	testCases := []struct {
 		desc          string
@@ -78,3 +82,310 @@ func TestGlobalCurrencyConverter(t *testing.T) {
 
 	assert.Equal(t, amount, convertedAmount, "Converted amount should equal input amount for 1:1 conversion ratio")
 }
+
+func TestCurrencies(t *testing.T) {
+	currencies := NewCurrencyService(config.CurrencyConfig{}, nil).Currencies()
+
+	assert.Len(t, currencies, len(currencyCodes))
+
+	byCode := map[string]dto.Currency{}
+	for _, currency := range currencies {
+		byCode[currency.Code] = currency
+	}
+	assert.Equal(t, dto.Currency{Code: "EUR", NumericCode: "978", Decimals: 2, Symbol: "€"}, byCode["EUR"])
+	assert.Equal(t, 0, byCode["JPY"].Decimals)
+	assert.Equal(t, 3, byCode["KWD"].Decimals)
+}
+
+func TestRates(t *testing.T) {
+	currencyService := NewCurrencyService(config.CurrencyConfig{}, nil)
+
+	testCases := []struct {
+		desc          string
+		base          string
+		expectedError error
+	}{
+		{
+			desc: "Known base",
+			base: "EUR",
+		},
+		{
+			desc:          "Unknown base",
+			base:          "XXX1",
+			expectedError: ErrUnknownCurrency,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			rates, err := currencyService.Rates(tc.base)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, rates, len(currencyCodes))
+			for code, rate := range rates {
+				converted, err := currencyService.Convert(1, tc.base, code)
+				assert.NoError(t, err)
+				assert.Equal(t, converted, rate, code)
+			}
+		})
+	}
+}
+
+func TestConvertWithOptions(t *testing.T) {
+	currencyService := NewCurrencyService(config.CurrencyConfig{}, nil)
+
+	testCases := []struct {
+		desc           string
+		amount         float64
+		targetCurr     string
+		opts           dto.ConversionOptions
+		expectedAmount float64
+		expectedRate   float64
+		expectedError  error
+	}{
+		{
+			desc:           "Plain rate",
+			amount:         100.005,
+			targetCurr:     "EUR",
+			expectedAmount: 100.005,
+			expectedRate:   1,
+		},
+		{
+			desc:           "Spread",
+			amount:         100,
+			targetCurr:     "EUR",
+			opts:           dto.ConversionOptions{SpreadBps: 150},
+			expectedAmount: 101.5,
+			expectedRate:   1.015,
+		},
+		{
+			desc:           "Half-even rounds ties to even",
+			amount:         10.125,
+			targetCurr:     "EUR",
+			opts:           dto.ConversionOptions{Rounding: config.RoundingHalfEven},
+			expectedAmount: 10.12,
+			expectedRate:   1,
+		},
+		{
+			desc:           "Up",
+			amount:         10.121,
+			targetCurr:     "EUR",
+			opts:           dto.ConversionOptions{Rounding: config.RoundingUp},
+			expectedAmount: 10.13,
+			expectedRate:   1,
+		},
+		{
+			desc:           "Down keeps exact amounts",
+			amount:         10.35,
+			targetCurr:     "EUR",
+			opts:           dto.ConversionOptions{Rounding: config.RoundingDown},
+			expectedAmount: 10.35,
+			expectedRate:   1,
+		},
+		{
+			desc:           "Rounded to the target minor units",
+			amount:         1234.5,
+			targetCurr:     "JPY",
+			opts:           dto.ConversionOptions{Rounding: config.RoundingDown},
+			expectedAmount: 1234,
+			expectedRate:   1,
+		},
+		{
+			desc:          "Unknown currency",
+			amount:        100,
+			targetCurr:    "INVALID",
+			expectedError: ErrUnknownCurrency,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			conversion, err := currencyService.ConvertWithOptions(tc.amount, "USD", tc.targetCurr, tc.opts)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expectedAmount, conversion.Amount, 1e-9)
+			assert.Equal(t, tc.expectedRate, conversion.Rate)
+		})
+	}
+}
+
+func TestOptions(t *testing.T) {
+	currencyService := NewCurrencyService(config.CurrencyConfig{
+		SpreadBps:                100,
+		Spreads:                  map[string]int{"USD/EUR": 25},
+		Rounding:                 config.RoundingUp,
+		ChargeInSupplierCurrency: true,
+	}, nil)
+
+	testCases := []struct {
+		desc       string
+		sourceCurr string
+		targetCurr string
+		expected   dto.ConversionOptions
+	}{
+		{
+			desc:       "Pair spread",
+			sourceCurr: "USD",
+			targetCurr: "EUR",
+			expected:   dto.ConversionOptions{SpreadBps: 25, Rounding: config.RoundingUp, DisplayOnly: true},
+		},
+		{
+			desc:       "Default spread",
+			sourceCurr: "EUR",
+			targetCurr: "USD",
+			expected:   dto.ConversionOptions{SpreadBps: 100, Rounding: config.RoundingUp, DisplayOnly: true},
+		},
+		{
+			desc:       "Same currency has no spread",
+			sourceCurr: "EUR",
+			targetCurr: "EUR",
+			expected:   dto.ConversionOptions{Rounding: config.RoundingUp, DisplayOnly: true},
+		},
+		{
+			desc:       "Lower-case pair spread",
+			sourceCurr: "usd",
+			targetCurr: "eur",
+			expected:   dto.ConversionOptions{SpreadBps: 25, Rounding: config.RoundingUp, DisplayOnly: true},
+		},
+		{
+			desc:       "Same currency in another case has no spread",
+			sourceCurr: "EUR",
+			targetCurr: "eur",
+			expected:   dto.ConversionOptions{Rounding: config.RoundingUp, DisplayOnly: true},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.expected, currencyService.Options(tc.sourceCurr, tc.targetCurr))
+		})
+	}
+}
+
+func TestConvertAt(t *testing.T) {
+	history := NewRateStore()
+	_, err := history.Load(strings.NewReader("date,base,currency,rate\n2024-01-01,USD,EUR,0.9\n"))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		desc           string
+		history        RateStore
+		expectedAmount float64
+		expectedAsOf   string
+		expectedError  error
+	}{
+		{
+			desc:           "Historical rate",
+			history:        history,
+			expectedAmount: 90,
+			expectedAsOf:   "2024-01-01",
+		},
+		{
+			desc:           "Without history",
+			expectedAmount: 100,
+			expectedAsOf:   "2024-01-15",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			currencyService := NewCurrencyService(config.CurrencyConfig{}, tc.history)
+
+			conversion, err := currencyService.ConvertAt(100, "USD", "EUR", time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expectedAmount, conversion.Amount, 1e-9)
+			assert.Equal(t, tc.expectedAsOf, conversion.AsOf.Format(dateLayout))
+		})
+	}
+}
+
+func TestConvertCurrent(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		rounding       string
+		amount         float64
+		targetCurr     string
+		expectedAmount float64
+	}{
+		{desc: "Half-even", rounding: config.RoundingHalfEven, amount: 1.015, targetCurr: "EUR", expectedAmount: 1.02},
+		{desc: "Half-even to even", rounding: config.RoundingHalfEven, amount: 1.025, targetCurr: "EUR", expectedAmount: 1.02},
+		{desc: "Up", rounding: config.RoundingUp, amount: 1.011, targetCurr: "EUR", expectedAmount: 1.02},
+		{desc: "Down", rounding: config.RoundingDown, amount: 1.019, targetCurr: "EUR", expectedAmount: 1.01},
+		{desc: "Whole yen", rounding: config.RoundingHalfEven, amount: 12.5, targetCurr: "JPY", expectedAmount: 12},
+		{desc: "Zero policy rounds half-even", amount: 2.5, targetCurr: "JPY", expectedAmount: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			currencyService := NewCurrencyService(config.CurrencyConfig{Rounding: tc.rounding}, nil)
+
+			conversion, err := currencyService.ConvertCurrent(tc.amount, "USD", tc.targetCurr)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAmount, conversion.Amount)
+			assert.Equal(t, 1.0, conversion.Rate)
+			assert.True(t, conversion.AsOf.IsZero())
+		})
+	}
+
+	_, err := NewCurrencyService(config.CurrencyConfig{}, nil).ConvertCurrent(1, "USD", "XXX")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
+
+func TestRateGaps(t *testing.T) {
+	from, to := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	report := NewCurrencyService(config.CurrencyConfig{}, nil).RateGaps(from, to)
+	assert.Equal(t, 3, report.Days)
+	assert.Empty(t, report.MissingDays)
+
+	report = NewCurrencyService(config.CurrencyConfig{}, NewRateStore()).RateGaps(from, to)
+	assert.Equal(t, []string{"2024-01-01", "2024-01-02", "2024-01-03"}, report.MissingDays)
+}
+
+func TestRatesAsOf(t *testing.T) {
+	assert.True(t, NewCurrencyService(config.CurrencyConfig{}, nil).RatesAsOf().IsZero(), "the static table has no date")
+	assert.True(t, NewCurrencyService(config.CurrencyConfig{}, NewRateStore()).RatesAsOf().IsZero())
+
+	history := newLoadedRateStore(t)
+	assert.Equal(t, history.LoadedAt(), NewCurrencyService(config.CurrencyConfig{}, history).RatesAsOf())
+}
+
+func TestConvert_WithHistory(t *testing.T) {
+	history := NewRateStore()
+	_, err := history.Load(strings.NewReader("date,base,currency,rate\n2024-01-01,USD,EUR,0.9\n2024-01-02,USD,EUR,0.8\n"))
+	assert.NoError(t, err)
+
+	currencyService := NewCurrencyService(config.CurrencyConfig{}, history)
+
+	converted, err := currencyService.Convert(100, "USD", "EUR")
+	assert.NoError(t, err)
+	assert.InDelta(t, 80, converted, 1e-9, "current conversion uses the latest historical rate")
+
+	_, err = currencyService.Convert(100, "USD", "GBP")
+	assert.ErrorIs(t, err, ErrRateNotFound, "a pair without history is not converted with the static table")
+	_, err = currencyService.ConvertAt(100, "USD", "GBP", time.Now())
+	assert.ErrorIs(t, err, ErrRateNotFound)
+
+	converted, err = currencyService.Convert(100, "GBP", "GBP")
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, converted)
+
+	rates, err := currencyService.Rates("USD")
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.8, rates["EUR"], 1e-9)
+
+	_, err = currencyService.Convert(100, "USD", "XXX")
+	assert.ErrorIs(t, err, ErrUnknownCurrency)
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+// applySupplierFilters passes the filters Hotelbeds can apply itself on to the availability request. The
+// price bounds are converted back into the supplier currency; rateFilter stays the exact check, as it
+// is for the rate class, which Hotelbeds cannot filter.
+func (h *HotelServiceImpl) applySupplierFilters(ctx context.Context, request *dto.HotelBedsSearchRequest, params dto.HotelSearchServiceParams) {
+	filters := params.Filters
+	if len(filters.Boards) > 0 {
+		request.Boards = &dto.BoardsFilter{Included: true}
+		for _, board := range filters.Boards {
+			request.Boards.Board = append(request.Boards.Board, board.HotelbedsCodes()...)
+		}
+		sort.Strings(request.Boards.Board)
+	}
+
+	minRate, maxRate := h.supplierPriceBounds(ctx, filters, params.Currency)
+
+	if filters.PaymentType != "" || filters.Packaging != nil || minRate > 0 || maxRate > 0 {
+		request.Filter = &dto.SearchFilter{
+			PaymentType: filters.PaymentType,
+			Packaging:   filters.Packaging,
+			MinRate:     minRate,
+			MaxRate:     maxRate,
+		}
+	}
+}
+
+// supplierPriceBounds converts the price bounds, which are selling prices in the requested currency, into
+// net rates in the supplier currency by undoing the FX spread and the tenant markup. Each bound is widened
+// by a minor unit of both currencies, so rounding never makes Hotelbeds drop a rate rateFilter would keep.
+// A bound that cannot be converted is not sent.
+func (h *HotelServiceImpl) supplierPriceBounds(ctx context.Context, filters dto.RateFilters, currency string) (minRate, maxRate float64) {
+	if h.supplierCurrency == "" || (filters.MinPrice == 0 && filters.MaxPrice == 0) {
+		return 0, 0
+	}
+
+	supplier, target := money.GetCurrency(h.supplierCurrency), money.GetCurrency(currency)
+	if supplier == nil || target == nil {
+		return 0, 0
+	}
+
+	// The rate of the selling price, without rounding, and the markup applied before it
+	opts := h.currService.Options(h.supplierCurrency, currency)
+	opts.Rounding = ""
+	conversion, err := h.currService.ConvertWithOptions(1, h.supplierCurrency, currency, opts)
+	factor := conversion.Rate * applyMarkup(ctx, 1)
+	if err != nil || factor <= 0 {
+		slog.WarnContext(ctx, "price bounds not sent to hotelbeds", slog.Any("error", err))
+		return 0, 0
+	}
+
+	targetUnit, supplierScale := math.Pow10(-target.Fraction), math.Pow10(supplier.Fraction)
+
+	if filters.MinPrice > 0 {
+		minRate = math.Max(math.Floor((filters.MinPrice-targetUnit)/factor*supplierScale)/supplierScale, 0)
+	}
+
+	if filters.MaxPrice > 0 {
+		maxRate = math.Ceil((filters.MaxPrice+targetUnit)/factor*supplierScale) / supplierScale
+	}
+
+	return minRate, maxRate
+}
+
+// rateFilter returns the check every priced rate must pass, or nil when the search does not filter rates.
+// Filters sent to the supplier are checked again so the priced rate never depends on them.
+func (h *HotelServiceImpl) rateFilter(ctx context.Context, hotel dto.Hotel, params dto.HotelSearchServiceParams, now time.Time) func(dto.Rate) bool {
+	filters := params.Filters
+	if !filters.Active() {
+		return nil
+	}
+
+	return func(rate dto.Rate) bool {
+		if filters.Refundable && !isRefundable(rate, now) {
+			return false
+		}
+
+		if len(filters.Boards) > 0 && !slices.Contains(filters.Boards, dto.BoardFromHotelbeds(rate.BoardCode)) {
+			return false
+		}
+
+		if filters.PaymentType != "" && rate.PaymentType != filters.PaymentType {
+			return false
+		}
+
+		if filters.Packaging != nil && rate.Packaging != *filters.Packaging {
+			return false
+		}
+
+		if filters.RateClass != "" && rate.RateClass != filters.RateClass {
+			return false
+		}
+
+		if filters.MinPrice > 0 || filters.MaxPrice > 0 {
+			return h.inPriceRange(ctx, rate, hotel.Currency, params.Currency, filters)
+		}
+
+		return true
+	}
+}
+
+// inPriceRange compares the selling price of the rate, as it would be returned, with the price bounds
+func (h *HotelServiceImpl) inPriceRange(ctx context.Context, rate dto.Rate, hotelCurrency, currency string, filters dto.RateFilters) bool {
+	net, err := strconv.ParseFloat(rate.Net, 64)
+	if err != nil {
+		return false
+	}
+
+	price, err := h.sellingPrice(ctx, net, hotelCurrency, currency)
+	if err != nil {
+		return false
+	}
+
+	if filters.MinPrice > 0 && price < filters.MinPrice {
+		return false
+	}
+
+	return filters.MaxPrice == 0 || price <= filters.MaxPrice
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrInvalidFlexibleSearch is returned when the requested window cannot be searched
+var ErrInvalidFlexibleSearch = errors.New("invalid flexible search")
+
+type FlexibleSearchService interface {
+	SearchFlexible(ctx context.Context, params dto.FlexibleSearchServiceParams) (dto.FlexibleSearchServiceResponse, error)
+}
+
+type FlexibleSearchServiceImpl struct {
+	hotelService HotelService
+	concurrency  int
+	maxCheckIns  int
+}
+
+func NewFlexibleSearchService(hotelService HotelService, cfg config.SearchConfig) FlexibleSearchService {
+	return &FlexibleSearchServiceImpl{
+		hotelService: hotelService,
+		concurrency:  max(1, cfg.FlexibleConcurrency),
+		maxCheckIns:  cfg.FlexibleMaxCheckIns,
+	}
+}
+
+type stayResult struct {
+	stay     dto.Stay
+	response dto.HotelSearchServiceResponse
+	err      error
+}
+
+// SearchFlexible runs one hotel search per candidate stay, at most concurrency at a time, and
+// collects the prices into a calendar per hotel. It fails only when every search fails.
+func (f *FlexibleSearchServiceImpl) SearchFlexible(ctx context.Context, params dto.FlexibleSearchServiceParams) (result dto.FlexibleSearchServiceResponse, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "FlexibleSearchService.SearchFlexible")
+	defer func() {
+		if err != nil {
+			telemetry.RecordError(span, err)
+			slog.ErrorContext(ctx, "flexible search failed", slog.String("error", err.Error()))
+		}
+		span.End()
+	}()
+
+	stays, err := f.candidateStays(params)
+	if err != nil {
+		return result, err
+	}
+
+	span.SetAttributes(attribute.Int("flexible.candidates", len(stays)), attribute.Int("flexible.nights", params.Nights))
+	slog.InfoContext(
+		ctx,
+		"searching flexible dates",
+		slog.String("windowStart", params.WindowStart),
+		slog.String("windowEnd", params.WindowEnd),
+		slog.Int("nights", params.Nights),
+		slog.Int("candidates", len(stays)),
+	)
+
+	results := make([]stayResult, len(stays))
+	semaphore := make(chan struct{}, f.concurrency)
+
+	var wg sync.WaitGroup
+	for i, stay := range stays {
+		wg.Add(1)
+		go func(i int, stay dto.Stay) {
+			defer wg.Done()
+			results[i].stay = stay
+
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i].err = ctx.Err()
+				return
+			}
+
+			results[i].response, results[i].err = f.hotelService.SearchHotels(ctx, dto.HotelSearchServiceParams{
+				CheckIn:     stay.CheckIn,
+				CheckOut:    stay.CheckOut,
+				HotelIDs:    params.HotelIDs,
+				Destination: params.Destination,
+				Geolocation: params.Geolocation,
+				Currency:    params.Currency,
+				Occupancies: params.Occupancies,
+				Filters:     params.Filters,
+			})
+		}(i, stay)
+	}
+	wg.Wait()
+
+	var lastErr error
+	for _, r := range results {
+		if r.err != nil {
+			lastErr = r.err
+			result.FailedCheckIns = append(result.FailedCheckIns, r.stay.CheckIn)
+			slog.WarnContext(ctx, "flexible search date failed", slog.String("checkIn", r.stay.CheckIn), slog.String("error", r.err.Error()))
+		}
+	}
+
+	if len(result.FailedCheckIns) == len(stays) {
+		return result, fmt.Errorf("all %d date searches failed: %w", len(stays), lastErr)
+	}
+
+	result.Calendars = buildCalendars(params, results)
+	result.Warnings = mergeWarnings(results)
+	slog.InfoContext(ctx, "flexible search completed", slog.Int("hotels", len(result.Calendars)), slog.Int("failed", len(result.FailedCheckIns)))
+
+	return result, nil
+}
+
+// candidateStays returns every stay of the requested length that starts and ends inside the window
+func (f *FlexibleSearchServiceImpl) candidateStays(params dto.FlexibleSearchServiceParams) ([]dto.Stay, error) {
+	start, err := time.Parse(dateLayout, params.WindowStart)
+	if err != nil {
+		return nil, fmt.Errorf("%w: window start must be in format YYYY-MM-DD", ErrInvalidFlexibleSearch)
+	}
+
+	end, err := time.Parse(dateLayout, params.WindowEnd)
+	if err != nil {
+		return nil, fmt.Errorf("%w: window end must be in format YYYY-MM-DD", ErrInvalidFlexibleSearch)
+	}
+
+	if params.Nights < 1 {
+		return nil, fmt.Errorf("%w: nights must be at least 1", ErrInvalidFlexibleSearch)
+	}
+
+	latest := end.AddDate(0, 0, -params.Nights)
+	if latest.Before(start) {
+		return nil, fmt.Errorf("%w: a %d night stay does not fit between %v and %v", ErrInvalidFlexibleSearch, params.Nights, params.WindowStart, params.WindowEnd)
+	}
+
+	candidates := int(latest.Sub(start).Hours()/24) + 1
+	if candidates > f.maxCheckIns {
+		return nil, fmt.Errorf("%w: window allows %d check-in dates, the maximum is %d", ErrInvalidFlexibleSearch, candidates, f.maxCheckIns)
+	}
+
+	stays := make([]dto.Stay, 0, candidates)
+	for checkIn := start; !checkIn.After(latest); checkIn = checkIn.AddDate(0, 0, 1) {
+		stays = append(stays, dto.Stay{
+			CheckIn:  checkIn.Format(dateLayout),
+			CheckOut: checkIn.AddDate(0, 0, params.Nights).Format(dateLayout),
+		})
+	}
+
+	return stays, nil
+}
+
+// buildCalendars groups the prices by hotel and by check-in date, in the requested hotel order or, for
+// destination and geolocation searches, from the cheapest stay up; hotels that were not available on any
+// date are left out
+func buildCalendars(params dto.FlexibleSearchServiceParams, results []stayResult) []dto.HotelCalendar {
+	calendars := map[string]*dto.HotelCalendar{}
+	for _, r := range results {
+		if r.err != nil {
+			continue
+		}
+
+		for _, price := range r.response.HotelPrices {
+			entry := dto.CalendarPrice{CheckIn: r.stay.CheckIn, CheckOut: r.stay.CheckOut, Price: price.Price}
+
+			calendar, ok := calendars[price.HotelID]
+			if !ok {
+				calendar = &dto.HotelCalendar{HotelID: price.HotelID, Currency: price.Currency, Cheapest: entry}
+				calendars[price.HotelID] = calendar
+			}
+
+			if entry.Price < calendar.Cheapest.Price {
+				calendar.Cheapest = entry
+			}
+			calendar.Calendar = append(calendar.Calendar, entry)
+		}
+	}
+
+	result := make([]dto.HotelCalendar, 0, len(calendars))
+	for _, id := range params.HotelIDs {
+		if calendar, ok := calendars[id]; ok {
+			result = append(result, *calendar)
+			delete(calendars, id)
+		}
+	}
+
+	rest := make([]dto.HotelCalendar, 0, len(calendars))
+	for _, calendar := range calendars {
+		rest = append(rest, *calendar)
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if rest[i].Cheapest.Price != rest[j].Cheapest.Price {
+			return rest[i].Cheapest.Price < rest[j].Cheapest.Price
+		}
+		return rest[i].HotelID < rest[j].HotelID
+	})
+	result = append(result, rest...)
+
+	return result
+}
+
+// mergeWarnings returns the warnings of the date searches once each, in the order they first appear
+func mergeWarnings(results []stayResult) []string {
+	var warnings []string
+	seen := map[string]bool{}
+	for _, r := range results {
+		for _, warning := range r.response.Warnings {
+			if !seen[warning] {
+				seen[warning] = true
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubHotelService prices each stay by its check-in date and fails the dates in failOn
+type stubHotelService struct {
+	prices   map[string]float64
+	failOn   map[string]bool
+	delay    time.Duration
+	mu       sync.Mutex
+	calls    []dto.HotelSearchServiceParams
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (s *stubHotelService) SearchHotels(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+	current := s.inFlight.Add(1)
+	defer s.inFlight.Add(-1)
+	for peak := s.peak.Load(); current > peak && !s.peak.CompareAndSwap(peak, current); peak = s.peak.Load() {
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, params)
+	s.mu.Unlock()
+
+	time.Sleep(s.delay)
+
+	if s.failOn[params.CheckIn] {
+		return dto.HotelSearchServiceResponse{}, errors.New("supplier error")
+	}
+
+	price, ok := s.prices[params.CheckIn]
+	if !ok {
+		return dto.HotelSearchServiceResponse{}, nil
+	}
+
+	return dto.HotelSearchServiceResponse{
+		HotelPrices: []dto.HotelPrice{
+			{HotelID: "5678", Currency: params.Currency, Price: price + 50},
+			{HotelID: "1234", Currency: params.Currency, Price: price},
+		},
+	}, nil
+}
+
+func flexibleParams(start, end string, nights int) dto.FlexibleSearchServiceParams {
+	return dto.FlexibleSearchServiceParams{
+		WindowStart: start,
+		WindowEnd:   end,
+		Nights:      nights,
+		HotelIDs:    []string{"1234", "5678"},
+		Currency:    "EUR",
+		Occupancies: []dto.Occupancy{{Rooms: 1, Adults: 2}},
+	}
+}
+
+func TestSearchFlexible(t *testing.T) {
+	hotelService := &stubHotelService{
+		prices: map[string]float64{"2030-06-01": 300, "2030-06-02": 250, "2030-06-03": 280},
+		failOn: map[string]bool{"2030-06-04": true},
+	}
+	service := NewFlexibleSearchService(hotelService, config.SearchConfig{FlexibleConcurrency: 2, FlexibleMaxCheckIns: 10})
+
+	result, err := service.SearchFlexible(context.Background(), flexibleParams("2030-06-01", "2030-06-07", 3))
+	assert.NoError(t, err)
+
+	// Check-ins 06-01 to 06-04 fit a three night stay in the window
+	assert.Len(t, hotelService.calls, 4)
+	for _, call := range hotelService.calls {
+		checkIn, _ := time.Parse(dateLayout, call.CheckIn)
+		assert.Equal(t, checkIn.AddDate(0, 0, 3).Format(dateLayout), call.CheckOut)
+		assert.Equal(t, []string{"1234", "5678"}, call.HotelIDs)
+	}
+
+	assert.Equal(t, []string{"2030-06-04"}, result.FailedCheckIns)
+	assert.Len(t, result.Calendars, 2)
+
+	calendar := result.Calendars[0]
+	assert.Equal(t, "1234", calendar.HotelID)
+	assert.Equal(t, "EUR", calendar.Currency)
+	assert.Equal(t, dto.CalendarPrice{CheckIn: "2030-06-02", CheckOut: "2030-06-05", Price: 250}, calendar.Cheapest)
+	assert.Equal(t, []string{"2030-06-01", "2030-06-02", "2030-06-03"}, []string{
+		calendar.Calendar[0].CheckIn, calendar.Calendar[1].CheckIn, calendar.Calendar[2].CheckIn,
+	})
+	assert.Equal(t, "5678", result.Calendars[1].HotelID)
+	assert.Equal(t, 300.0, result.Calendars[1].Cheapest.Price)
+}
+
+func TestSearchFlexible_Destination(t *testing.T) {
+	hotelService := &stubHotelService{prices: map[string]float64{"2030-06-01": 300, "2030-06-02": 250}}
+	service := NewFlexibleSearchService(hotelService, config.Default().Search)
+
+	params := flexibleParams("2030-06-01", "2030-06-04", 2)
+	params.HotelIDs, params.Destination = nil, "PMI"
+
+	result, err := service.SearchFlexible(context.Background(), params)
+	assert.NoError(t, err)
+
+	for _, call := range hotelService.calls {
+		assert.Equal(t, "PMI", call.Destination)
+	}
+
+	// Without a requested order the cheapest hotel comes first
+	assert.Len(t, result.Calendars, 2)
+	assert.Equal(t, "1234", result.Calendars[0].HotelID)
+	assert.Equal(t, "5678", result.Calendars[1].HotelID)
+}
+
+func TestSearchFlexible_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        dto.FlexibleSearchServiceParams
+		failOn        map[string]bool
+		invalid       bool
+		expectedError string
+	}{
+		{
+			name:          "Stay does not fit the window",
+			params:        flexibleParams("2030-06-01", "2030-06-03", 3),
+			invalid:       true,
+			expectedError: "does not fit",
+		},
+		{
+			name:          "Too many check-in dates",
+			params:        flexibleParams("2030-06-01", "2030-08-01", 1),
+			invalid:       true,
+			expectedError: "the maximum is 31",
+		},
+		{
+			name:          "Invalid nights",
+			params:        flexibleParams("2030-06-01", "2030-06-03", 0),
+			invalid:       true,
+			expectedError: "nights must be at least 1",
+		},
+		{
+			name:          "Every date fails",
+			params:        flexibleParams("2030-06-01", "2030-06-03", 1),
+			failOn:        map[string]bool{"2030-06-01": true, "2030-06-02": true},
+			expectedError: "all 2 date searches failed: supplier error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewFlexibleSearchService(&stubHotelService{failOn: tt.failOn}, config.Default().Search)
+
+			_, err := service.SearchFlexible(context.Background(), tt.params)
+			assert.ErrorContains(t, err, tt.expectedError)
+			assert.Equal(t, tt.invalid, errors.Is(err, ErrInvalidFlexibleSearch))
+		})
+	}
+}
+
+func TestSearchFlexible_BoundedConcurrency(t *testing.T) {
+	hotelService := &stubHotelService{delay: 10 * time.Millisecond}
+	service := NewFlexibleSearchService(hotelService, config.SearchConfig{FlexibleConcurrency: 3, FlexibleMaxCheckIns: 31})
+
+	_, err := service.SearchFlexible(context.Background(), flexibleParams("2030-06-01", "2030-06-15", 2))
+	assert.NoError(t, err)
+
+	assert.Len(t, hotelService.calls, 13)
+	assert.LessOrEqual(t, hotelService.peak.Load(), int32(3))
+	assert.Greater(t, hotelService.peak.Load(), int32(1))
+}
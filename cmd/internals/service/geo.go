@@ -0,0 +1,47 @@
+package service
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+const (
+	earthRadiusKm   = 6371.0
+	kilometersPerMi = 1.609344
+)
+
+// hotelDistance returns the great-circle distance between the searched point and the hotel in the
+// searched unit, or nil when the supplier did not send the hotel's coordinates
+func hotelDistance(geo dto.Geolocation, hotel dto.Hotel) *float64 {
+	latitude, err := strconv.ParseFloat(hotel.Latitude, 64)
+	if err != nil {
+		return nil
+	}
+
+	longitude, err := strconv.ParseFloat(hotel.Longitude, 64)
+	if err != nil {
+		return nil
+	}
+
+	distance := distanceKm(geo.Latitude, geo.Longitude, latitude, longitude)
+	if geo.Unit == dto.UnitMiles {
+		distance /= kilometersPerMi
+	}
+
+	distance = math.Round(distance*100) / 100
+	return &distance
+}
+
+// distanceKm is the haversine distance between two coordinates
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// fixturesDir holds the supplier exchanges captured by the recording client
+const fixturesDir = "../client/testdata/fixtures"
+
+func assertGolden(t *testing.T, name string, got any) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(got, "", "  ")
+	assert.NoError(t, err)
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		assert.NoError(t, os.MkdirAll("testdata", 0o755))
+		assert.NoError(t, os.WriteFile(path, append(actual, '\n'), 0o644))
+	}
+
+	expected, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actual))
+}
+
+func TestSearchHotels_Golden(t *testing.T) {
+	tests := []struct {
+		name   string
+		golden string
+		params dto.HotelSearchServiceParams
+	}{
+		{
+			name:   "Two hotels, two nights",
+			golden: "search_hotels_eur.golden.json",
+			params: dto.HotelSearchServiceParams{
+				CheckIn:     "2030-06-01",
+				CheckOut:    "2030-06-03",
+				HotelIDs:    []string{"1234", "5678"},
+				Currency:    "EUR",
+				Occupancies: []dto.Occupancy{{Rooms: 1, Adults: 2}},
+			},
+		},
+		{
+			name:   "One hotel with a child",
+			golden: "search_hotels_gbp.golden.json",
+			params: dto.HotelSearchServiceParams{
+				CheckIn:     "2030-06-10",
+				CheckOut:    "2030-06-11",
+				HotelIDs:    []string{"9012"},
+				Currency:    "GBP",
+				Occupancies: []dto.Occupancy{{Rooms: 1, Adults: 2, Children: 1}},
+			},
+		},
+	}
+
+	replayer := client.NewRecordingClient(nil, config.RecorderConfig{Mode: config.RecorderModeReplay, Dir: fixturesDir})
+	service := NewHotelService(replayer, NewCurrencyService(config.Default().Currency, nil), nil, "")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := service.SearchHotels(context.Background(), tt.params)
+			assert.NoError(t, err)
+			assertGolden(t, tt.golden, result.HotelPrices)
+		})
+	}
+}
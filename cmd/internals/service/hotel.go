@@ -1,31 +1,74 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
 
+	"github.com/Rhymond/go-money"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/idmap"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+const dateLayout = "2006-01-02"
+
 type HotelService interface {
-	SearchHotels(dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error)
+	SearchHotels(context.Context, dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error)
 }
 
 type HotelServiceImpl struct {
 	client      client.HotelBedsClient
 	currService CurrencyService
+	mapper      idmap.Mapper
+	// supplierCurrency is the Hotelbeds account currency; when empty price bounds are only checked locally
+	supplierCurrency string
 }
 
-func NewHotelService() HotelService {
+// NewHotelService creates the hotel service; a nil mapper uses the Hotelbeds codes as liteAPI hotel IDs and
+// an empty supplier currency never sends price bounds to Hotelbeds
+func NewHotelService(client client.HotelBedsClient, currService CurrencyService, mapper idmap.Mapper, supplierCurrency string) HotelService {
+	if mapper == nil {
+		mapper = idmap.Identity()
+	}
+
 	return &HotelServiceImpl{
-		client:      client.NewHotelBedsClient(),
-		currService: NewCurrencyService(),
+		client:           client,
+		currService:      currService,
+		mapper:           mapper,
+		supplierCurrency: supplierCurrency,
 	}
 }
 
-func (h *HotelServiceImpl) SearchHotels(serviceParams dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
-	result := dto.HotelSearchServiceResponse{}
+func (h *HotelServiceImpl) SearchHotels(ctx context.Context, serviceParams dto.HotelSearchServiceParams) (result dto.HotelSearchServiceResponse, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "HotelService.SearchHotels")
+	span.SetAttributes(
+		attribute.Int("hotels.requested", len(serviceParams.HotelIDs)),
+		attribute.String("currency", serviceParams.Currency),
+	)
+	defer func() {
+		if err != nil {
+			telemetry.RecordError(span, err)
+			slog.ErrorContext(ctx, "hotel search failed", slog.String("error", err.Error()))
+		}
+		span.End()
+	}()
+
+	slog.InfoContext(
+		ctx,
+		"searching hotels",
+		slog.Int("hotels", len(serviceParams.HotelIDs)),
+		slog.String("destination", serviceParams.Destination),
+		slog.String("checkIn", serviceParams.CheckIn),
+		slog.String("checkOut", serviceParams.CheckOut),
+		slog.String("currency", serviceParams.Currency),
+	)
 
 	//create request
 	request := dto.HotelBedsSearchRequest{
@@ -34,11 +77,26 @@ func (h *HotelServiceImpl) SearchHotels(serviceParams dto.HotelSearchServicePara
 			CheckOut: serviceParams.CheckOut,
 		},
 		Occupancies: serviceParams.Occupancies,
-		Hotels: dto.HotelsFilter{
-			Hotel: serviceParams.HotelIDs,
-		},
 	}
 
+	// The handler guarantees exactly one search mode
+	switch {
+	case serviceParams.Geolocation != nil:
+		request.Geolocation = serviceParams.Geolocation
+	case serviceParams.Destination != "":
+		request.Destination = &dto.Destination{Code: serviceParams.Destination}
+	default:
+		codes, warnings := supplierCodes(h.mapper, serviceParams.HotelIDs)
+		result.Warnings = warnings
+		if len(codes) == 0 {
+			slog.WarnContext(ctx, "no requested hotel is mapped to hotelbeds", slog.Any("hotelIds", serviceParams.HotelIDs))
+			return result, nil
+		}
+		request.Hotels = &dto.HotelsFilter{Hotel: codes}
+	}
+
+	h.applySupplierFilters(ctx, &request, serviceParams)
+
 	// Convert request to JSON
 	byteRequest, err := json.Marshal(request)
 	if err != nil {
@@ -46,45 +104,270 @@ func (h *HotelServiceImpl) SearchHotels(serviceParams dto.HotelSearchServicePara
 	}
 
 	// get response from client
-	byteResponse, err := h.client.SearchHotels(byteRequest)
+	byteResponse, err := h.client.SearchHotels(ctx, byteRequest)
 	if err != nil {
 		return result, fmt.Errorf("failed to marshal response: %w", err)
 	}
 
 	// unmarshal response
-	response := dto.HotelbedsResponse{}
-	err = json.Unmarshal(byteResponse, &response)
+	response, err := h.decodeResponse(ctx, byteResponse)
 	if err != nil {
-		return result, fmt.Errorf("failed to unmarshal response: %w", err)
+		return result, err
+	}
+
+	nights, err := stayNights(serviceParams.CheckIn, serviceParams.CheckOut)
+	if err != nil {
+		return result, err
 	}
 
 	// get price for each hotel
+	now := time.Now()
+	var unmapped []int
 	for _, hotel := range response.Hotels.Hotels {
-		var price float64 = 0.0
-
-		price, err = hotel.GetPrice()
-		if err != nil {
-			return result, fmt.Errorf("failed to get Price for Hotel: %v", hotel.Code)
+		hotelID, ok := h.mapper.HotelID(idmap.SupplierHotelbeds, hotel.GetStringifiedHotelCode())
+		if !ok {
+			unmapped = append(unmapped, hotel.Code)
+			continue
 		}
 
-		if hotel.Currency != serviceParams.Currency {
-			price, err = h.currService.Convert(price, hotel.Currency, serviceParams.Currency)
-			if err != nil {
-				return result, fmt.Errorf("failed to convert Currency: %w", err)
-			}
+		hotelRes, ok, err := h.priceHotel(ctx, hotel, serviceParams, nights, now)
+		if err != nil {
+			return result, err
 		}
 
-		hotelRes := dto.HotelPrice{
-			HotelID:  hotel.GetStringifiedHotelCode(),
-			Currency: serviceParams.Currency,
-			Price:    price,
+		if ok {
+			hotelRes.HotelID = hotelID
+			result.HotelPrices = append(result.HotelPrices, hotelRes)
 		}
+	}
 
-		result.HotelPrices = append(result.HotelPrices, hotelRes)
+	// Hotels without a liteAPI ID cannot be booked through us, so they are left out rather than exposing the code
+	if len(unmapped) > 0 {
+		slog.WarnContext(ctx, "hotelbeds returned unmapped hotels", slog.Any("codes", unmapped))
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d of the hotels found have no liteAPI hotel id and were left out", len(unmapped)))
 	}
 
+	sortHotelPrices(result.HotelPrices, serviceParams.Sort)
+
 	result.SupplierResponse = string(byteResponse)
 	result.SupplierRequest = string(byteRequest)
+	span.SetAttributes(attribute.Int("hotels.returned", len(result.HotelPrices)))
+	slog.InfoContext(ctx, "hotel search completed", slog.Int("hotels", len(result.HotelPrices)))
 
 	return result, nil
 }
+
+// priceHotel converts the hotel's cheapest rate into the requested currency, with the tenant markup,
+// and breaks it down per night. With rate filters, the rates they reject are dropped first and
+// hotels left without rates are reported as not ok.
+func (h *HotelServiceImpl) priceHotel(ctx context.Context, hotel dto.Hotel, params dto.HotelSearchServiceParams, nights int, now time.Time) (dto.HotelPrice, bool, error) {
+	keep := h.rateFilter(ctx, hotel, params, now)
+
+	rate, hasRate := hotel.CheapestRate(keep)
+	if keep != nil && !hasRate {
+		return dto.HotelPrice{}, false, nil
+	}
+
+	price, err := hotel.GetPrice()
+	if keep != nil {
+		price, err = strconv.ParseFloat(rate.Net, 64)
+	}
+	if err != nil {
+		return dto.HotelPrice{}, false, fmt.Errorf("failed to get Price for Hotel: %v", hotel.Code)
+	}
+
+	supplierPrice := price
+	conversion, opts, err := h.convert(ctx, supplierPrice, hotel.Currency, params.Currency)
+	if err != nil {
+		return dto.HotelPrice{}, false, err
+	}
+	price = conversion.Amount
+
+	hotelRes := dto.HotelPrice{
+		HotelID:          hotel.GetStringifiedHotelCode(),
+		Name:             hotel.Name,
+		Currency:         params.Currency,
+		Price:            price,
+		Nights:           nights,
+		Total:            price,
+		PerNight:         roundPrice(price/float64(nights), params.Currency, opts),
+		Pricing:          dto.PriceBreakdown{Base: price, Total: price},
+		SupplierPrice:    supplierPrice,
+		SupplierCurrency: hotel.Currency,
+		AppliedRate:      conversion.Rate,
+		ChargePrice:      price,
+		ChargeCurrency:   params.Currency,
+	}
+
+	// Charging in the supplier currency only spares the guest the FX: the tenant markup still applies
+	if opts.DisplayOnly {
+		charge, _, err := h.convert(ctx, supplierPrice, hotel.Currency, hotel.Currency)
+		if err != nil {
+			return dto.HotelPrice{}, false, err
+		}
+		hotelRes.ChargePrice = charge.Amount
+		hotelRes.ChargeCurrency = hotel.Currency
+	}
+
+	if params.Geolocation != nil {
+		hotelRes.Distance = hotelDistance(*params.Geolocation, hotel)
+	}
+
+	if !hasRate {
+		return hotelRes, true, nil
+	}
+
+	hotelRes.Pricing, err = h.priceBreakdown(rate.Taxes, price, hotel.Currency, params.Currency)
+	if err != nil {
+		return dto.HotelPrice{}, false, err
+	}
+
+	hotelRes.Cancellation, err = h.cancellationTerms(ctx, rate, hotel, params.Currency, now)
+	if err != nil {
+		return dto.HotelPrice{}, false, err
+	}
+
+	if rate.BoardCode != "" {
+		hotelRes.Board = dto.BoardFromHotelbeds(rate.BoardCode)
+		hotelRes.BoardName = rate.BoardName
+	}
+
+	arrival, _ := time.Parse(dateLayout, params.CheckIn)
+	for _, daily := range rate.DailyRates {
+		net, err := strconv.ParseFloat(daily.DailyNet, 64)
+		if err != nil {
+			return dto.HotelPrice{}, false, fmt.Errorf("failed to get daily Price for Hotel: %v", hotel.Code)
+		}
+
+		net, err = h.sellingPrice(ctx, net, hotel.Currency, params.Currency)
+		if err != nil {
+			return dto.HotelPrice{}, false, err
+		}
+
+		hotelRes.DailyRates = append(hotelRes.DailyRates, dto.DailyPrice{
+			Date:  arrival.AddDate(0, 0, daily.Offset-1).Format(dateLayout),
+			Price: roundPrice(net, params.Currency, opts),
+		})
+	}
+
+	return hotelRes, true, nil
+}
+
+// priceBreakdown splits the selling price into base and included taxes, and adds the taxes and fees
+// payable at the property. Taxes are converted into the requested currency but never marked up.
+func (h *HotelServiceImpl) priceBreakdown(taxes *dto.Taxes, price float64, hotelCurrency, currency string) (dto.PriceBreakdown, error) {
+	pricing := dto.PriceBreakdown{Base: price, Total: price}
+	if taxes == nil {
+		return pricing, nil
+	}
+
+	for _, tax := range taxes.Taxes {
+		amount, err := strconv.ParseFloat(tax.Amount, 64)
+		if err != nil {
+			return pricing, fmt.Errorf("failed to parse tax amount: %v", tax.Amount)
+		}
+
+		// Taxes payable at the property are often quoted in the local currency
+		from := tax.Currency
+		if from == "" {
+			from = hotelCurrency
+		}
+
+		taxOpts := h.currService.Options(from, currency)
+		conversion, err := h.currService.ConvertWithOptions(amount, from, currency, taxOpts)
+		if err != nil {
+			return pricing, fmt.Errorf("failed to convert Currency: %w", err)
+		}
+		amount = roundPrice(conversion.Amount, currency, taxOpts)
+		if tax.Included {
+			pricing.IncludedTaxes += amount
+		} else {
+			pricing.PayableAtProperty += amount
+		}
+
+		pricing.Taxes = append(pricing.Taxes, dto.TaxAmount{Type: tax.Type, Included: tax.Included, Amount: amount})
+	}
+
+	opts := h.currService.Options(hotelCurrency, currency)
+	pricing.IncludedTaxes = roundPrice(pricing.IncludedTaxes, currency, opts)
+	pricing.PayableAtProperty = roundPrice(pricing.PayableAtProperty, currency, opts)
+	pricing.Base = roundPrice(price-pricing.IncludedTaxes, currency, opts)
+	pricing.Total = roundPrice(price+pricing.PayableAtProperty, currency, opts)
+
+	return pricing, nil
+}
+
+// sellingPrice converts a supplier amount into the requested currency and adds the tenant markup
+func (h *HotelServiceImpl) sellingPrice(ctx context.Context, amount float64, from, to string) (float64, error) {
+	conversion, _, err := h.convert(ctx, amount, from, to)
+	if err != nil {
+		return amount, err
+	}
+
+	return conversion.Amount, nil
+}
+
+// convert adds the tenant markup to a supplier amount and converts it with the FX policy of the pair.
+// The markup is added first so that rounding is the last step.
+func (h *HotelServiceImpl) convert(ctx context.Context, amount float64, from, to string) (dto.Conversion, dto.ConversionOptions, error) {
+	opts := h.currService.Options(from, to)
+
+	conversion, err := h.currService.ConvertWithOptions(applyMarkup(ctx, amount), from, to, opts)
+	if err != nil {
+		return conversion, opts, fmt.Errorf("failed to convert Currency: %w", err)
+	}
+
+	return conversion, opts, nil
+}
+
+// stayNights returns the number of nights between the validated check-in and check-out dates
+func stayNights(checkIn, checkOut string) (int, error) {
+	in, err := time.Parse(dateLayout, checkIn)
+	if err != nil {
+		return 0, fmt.Errorf("invalid check-in date: %v", checkIn)
+	}
+
+	out, err := time.Parse(dateLayout, checkOut)
+	if err != nil {
+		return 0, fmt.Errorf("invalid check-out date: %v", checkOut)
+	}
+
+	nights := int(out.Sub(in).Hours() / 24)
+	if nights < 1 {
+		return 0, fmt.Errorf("check-out %v must be after check-in %v", checkOut, checkIn)
+	}
+
+	return nights, nil
+}
+
+// roundPrice rounds a derived amount to the minor units of its currency with the rounding mode of the
+// FX policy, half-even when the policy leaves converted amounts unrounded
+func roundPrice(amount float64, currency string, opts dto.ConversionOptions) float64 {
+	return roundMinorUnits(amount, money.GetCurrency(currency).Fraction, opts.Rounding)
+}
+
+// applyMarkup adds the tenant's markup to the price, if the request is authenticated
+func applyMarkup(ctx context.Context, price float64) float64 {
+	tenant, ok := auth.TenantFromContext(ctx)
+	if !ok || tenant.MarkupPercent == 0 {
+		return price
+	}
+
+	return price * (1 + tenant.MarkupPercent/100)
+}
+
+// decodeResponse unmarshals the supplier payload inside its own span
+func (h *HotelServiceImpl) decodeResponse(ctx context.Context, byteResponse []byte) (dto.HotelbedsResponse, error) {
+	_, span := telemetry.StartSpan(ctx, "HotelService.decodeResponse")
+	span.SetAttributes(attribute.Int("response.size", len(byteResponse)))
+	defer span.End()
+
+	response := dto.HotelbedsResponse{}
+	if err := json.Unmarshal(byteResponse, &response); err != nil {
+		err = fmt.Errorf("failed to unmarshal response: %w", err)
+		telemetry.RecordError(span, err)
+		return response, err
+	}
+
+	return response, nil
+}
@@ -1,12 +1,21 @@
 package service
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/idmap"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service/mocks"
+	telemetrymocks "github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry/mocks"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func TestSearchHotels(t *testing.T) {
@@ -26,7 +35,7 @@ func TestSearchHotels(t *testing.T) {
 			params: dto.HotelSearchServiceParams{
 				CheckIn:  "2024-12-25",
 				CheckOut: "2024-12-26",
-				HotelIDs: []int{1234, 5678},
+				HotelIDs: []string{"1234", "5678"},
 				Currency: "EUR",
 				Occupancies: []dto.Occupancy{
 					{
@@ -46,7 +55,7 @@ func TestSearchHotels(t *testing.T) {
 			params: dto.HotelSearchServiceParams{
 				CheckIn:  "2024-12-25",
 				CheckOut: "2024-12-26",
-				HotelIDs: []int{1234},
+				HotelIDs: []string{"1234"},
 				Currency: "EUR",
 				Occupancies: []dto.Occupancy{
 					{
@@ -66,7 +75,7 @@ func TestSearchHotels(t *testing.T) {
 			params: dto.HotelSearchServiceParams{
 				CheckIn:  "2024-12-25",
 				CheckOut: "2024-12-26",
-				HotelIDs: []int{1234},
+				HotelIDs: []string{"1234"},
 				Currency: "EUR",
 				Occupancies: []dto.Occupancy{
 					{
@@ -86,7 +95,7 @@ func TestSearchHotels(t *testing.T) {
 			params: dto.HotelSearchServiceParams{
 				CheckIn:  "2024-12-25",
 				CheckOut: "2024-12-26",
-				HotelIDs: []int{1234, 5678},
+				HotelIDs: []string{"1234", "5678"},
 				Currency: "USD",
 				Occupancies: []dto.Occupancy{
 					{
@@ -106,7 +115,7 @@ func TestSearchHotels(t *testing.T) {
 			params: dto.HotelSearchServiceParams{
 				CheckIn:  "2024-12-25",
 				CheckOut: "2024-12-26",
-				HotelIDs: []int{1234, 5678},
+				HotelIDs: []string{"1234", "5678"},
 				Currency: "asd",
 				Occupancies: []dto.Occupancy{
 					{
@@ -125,7 +134,7 @@ func TestSearchHotels(t *testing.T) {
 			params: dto.HotelSearchServiceParams{
 				CheckIn:  "2024-12-25",
 				CheckOut: "2024-12-26",
-				HotelIDs: []int{1234, 5678},
+				HotelIDs: []string{"1234", "5678"},
 				Currency: "EUR",
 				Occupancies: []dto.Occupancy{
 					{
@@ -144,9 +153,10 @@ func TestSearchHotels(t *testing.T) {
 			hotelService := &HotelServiceImpl{
 				client:      tt.client,
 				currService: tt.currService,
+				mapper:      idmap.Identity(),
 			}
 
-			result, err := hotelService.SearchHotels(tt.params)
+			result, err := hotelService.SearchHotels(context.Background(), tt.params)
 
 			if tt.expectedError != "" {
 				assert.Error(t, err)
@@ -165,3 +175,683 @@ func TestSearchHotels(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchHotels_Tracing(t *testing.T) {
+	recorder := telemetrymocks.NewSpanRecorder()
+
+	hotelService := &HotelServiceImpl{
+		client:      &mocks.MockHotelBedsClient{InvalidResponse: true},
+		currService: &mocks.MockCurrencyService{},
+		mapper:      idmap.Identity(),
+	}
+
+	_, err := hotelService.SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+		HotelIDs: []string{"1234"},
+		Currency: "EUR",
+	})
+	assert.Error(t, err)
+
+	search := telemetrymocks.SpanByName(recorder, "HotelService.SearchHotels")
+	decode := telemetrymocks.SpanByName(recorder, "HotelService.decodeResponse")
+	assert.NotNil(t, search)
+	assert.NotNil(t, decode)
+
+	assert.Equal(t, search.SpanContext().SpanID(), decode.Parent().SpanID())
+	assert.Equal(t, codes.Error, decode.Status().Code)
+	assert.Equal(t, codes.Error, search.Status().Code)
+}
+
+func TestSearchHotels_TenantMarkup(t *testing.T) {
+	hotelService := &HotelServiceImpl{
+		client:      &mocks.MockHotelBedsClient{},
+		currService: &mocks.MockCurrencyService{},
+		mapper:      idmap.Identity(),
+	}
+
+	ctx := auth.WithTenant(context.Background(), auth.Tenant{ID: "acme", MarkupPercent: 10})
+	result, err := hotelService.SearchHotels(ctx, dto.HotelSearchServiceParams{
+		CheckIn:  "2024-12-25",
+		CheckOut: "2024-12-26",
+		HotelIDs: []string{"1234"},
+		Currency: "EUR",
+	})
+
+	assert.NoError(t, err)
+	assert.InDelta(t, 219.989, result.HotelPrices[0].Price, 0.0001)
+}
+
+func TestSearchHotels_NightlyBreakdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		client        *mocks.MockHotelBedsClient
+		checkOut      string
+		expectedDaily []dto.DailyPrice
+		expectedError string
+	}{
+		{
+			name:     "Daily rates of the cheapest rate",
+			client:   &mocks.MockHotelBedsClient{DailyRates: true},
+			checkOut: "2024-12-27",
+			expectedDaily: []dto.DailyPrice{
+				{Date: "2024-12-25", Price: 99.99},
+				{Date: "2024-12-26", Price: 100},
+			},
+		},
+		{
+			name:     "Without daily rates",
+			client:   &mocks.MockHotelBedsClient{},
+			checkOut: "2024-12-27",
+		},
+		{
+			name:          "Check-out before check-in",
+			client:        &mocks.MockHotelBedsClient{},
+			checkOut:      "2024-12-24",
+			expectedError: "must be after check-in",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelService := NewHotelService(tt.client, &mocks.MockCurrencyService{}, nil, "")
+
+			result, err := hotelService.SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+				CheckIn:  "2024-12-25",
+				CheckOut: tt.checkOut,
+				HotelIDs: []string{"1234"},
+				Currency: "EUR",
+			})
+
+			if tt.expectedError != "" {
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			price := result.HotelPrices[0]
+			assert.Equal(t, 2, price.Nights)
+			assert.Equal(t, 199.99, price.Total)
+			assert.Equal(t, price.Total, price.Price)
+			assert.Equal(t, 100.0, price.PerNight)
+			assert.Equal(t, tt.expectedDaily, price.DailyRates)
+		})
+	}
+}
+
+func TestSearchHotels_Taxes(t *testing.T) {
+	tests := []struct {
+		name            string
+		client          *mocks.MockHotelBedsClient
+		markupPercent   float64
+		expectedPricing dto.PriceBreakdown
+	}{
+		{
+			name:   "Included and payable at property",
+			client: &mocks.MockHotelBedsClient{Taxes: true},
+			expectedPricing: dto.PriceBreakdown{
+				Base:              272.72,
+				IncludedTaxes:     27.27,
+				PayableAtProperty: 9,
+				Total:             308.99,
+				Taxes: []dto.TaxAmount{
+					{Type: "TAX", Included: true, Amount: 27.27},
+					{Type: "TAXESANDFEES", Included: false, Amount: 9},
+				},
+			},
+		},
+		{
+			name:          "Markup is not applied to taxes",
+			client:        &mocks.MockHotelBedsClient{Taxes: true},
+			markupPercent: 10,
+			expectedPricing: dto.PriceBreakdown{
+				Base:              302.72,
+				IncludedTaxes:     27.27,
+				PayableAtProperty: 9,
+				Total:             338.99,
+				Taxes: []dto.TaxAmount{
+					{Type: "TAX", Included: true, Amount: 27.27},
+					{Type: "TAXESANDFEES", Included: false, Amount: 9},
+				},
+			},
+		},
+		{
+			name:            "Without taxes",
+			client:          &mocks.MockHotelBedsClient{},
+			expectedPricing: dto.PriceBreakdown{Base: 299.99, Total: 299.99},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelService := NewHotelService(tt.client, &mocks.MockCurrencyService{Rate: 0.9}, nil, "")
+
+			ctx := auth.WithTenant(context.Background(), auth.Tenant{ID: "acme", MarkupPercent: tt.markupPercent})
+			result, err := hotelService.SearchHotels(ctx, dto.HotelSearchServiceParams{
+				CheckIn:  "2024-12-25",
+				CheckOut: "2024-12-26",
+				HotelIDs: []string{"1234", "5678"},
+				Currency: "EUR",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPricing, result.HotelPrices[1].Pricing)
+		})
+	}
+}
+
+func TestSearchHotels_Cancellation(t *testing.T) {
+	tests := []struct {
+		name           string
+		client         *mocks.MockHotelBedsClient
+		refundable     bool
+		expectedHotels []string
+		expectedPrice  float64
+		expectedTerms  *dto.CancellationTerms
+		expectedErr    bool
+	}{
+		{
+			name:           "Cheapest rate is non-refundable",
+			client:         &mocks.MockHotelBedsClient{Cancellation: true},
+			expectedHotels: []string{"1234", "5678"},
+			expectedPrice:  199.99,
+			expectedTerms: &dto.CancellationTerms{
+				Refundable: false,
+				Penalties:  []dto.CancellationPenalty{{From: "2024-01-01T01:00:00+01:00", Amount: 199.99}},
+			},
+		},
+		{
+			name:           "Refundable filter drops non-refundable rates and hotels",
+			client:         &mocks.MockHotelBedsClient{Cancellation: true},
+			refundable:     true,
+			expectedHotels: []string{"1234"},
+			expectedPrice:  249.99,
+			expectedTerms: &dto.CancellationTerms{
+				Refundable:      true,
+				RefundableUntil: "2099-12-21T00:00:00+01:00",
+				Penalties: []dto.CancellationPenalty{
+					{From: "2099-12-21T00:00:00+01:00", Amount: 50},
+					{From: "2099-12-25T00:00:00+01:00", Amount: 249.99},
+				},
+			},
+		},
+		{
+			name:           "Refundable filter without rate details drops every hotel",
+			client:         &mocks.MockHotelBedsClient{},
+			refundable:     true,
+			expectedHotels: nil,
+		},
+		{
+			name:        "Invalid penalty amount",
+			client:      &mocks.MockHotelBedsClient{Cancellation: true, InvalidRate: true},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelService := NewHotelService(tt.client, &mocks.MockCurrencyService{}, nil, "")
+
+			result, err := hotelService.SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+				CheckIn:  "2099-12-25",
+				CheckOut: "2099-12-26",
+				HotelIDs: []string{"1234", "5678"},
+				Currency: "EUR",
+				Filters:  dto.RateFilters{Refundable: tt.refundable},
+			})
+
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var hotels []string
+			for _, price := range result.HotelPrices {
+				hotels = append(hotels, price.HotelID)
+			}
+			assert.Equal(t, tt.expectedHotels, hotels)
+
+			if len(result.HotelPrices) > 0 {
+				assert.Equal(t, tt.expectedPrice, result.HotelPrices[0].Price)
+				assert.Equal(t, tt.expectedTerms, result.HotelPrices[0].Cancellation)
+			}
+		})
+	}
+}
+
+func TestCancellationTerms_HotelTimezone(t *testing.T) {
+	hotelService := &HotelServiceImpl{currService: &mocks.MockCurrencyService{}}
+	rate := dto.Rate{
+		RateClass:            "NOR",
+		CancellationPolicies: []dto.CancellationPolicy{{Amount: "80.00", From: "2099-07-01T09:00:00Z"}},
+	}
+	now := time.Date(2099, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		destinationCode string
+		expectedFrom    string
+	}{
+		{name: "Known destination", destinationCode: "NYC", expectedFrom: "2099-07-01T05:00:00-04:00"},
+		{name: "Unknown destination keeps the supplier offset", destinationCode: "XXX", expectedFrom: "2099-07-01T09:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms, err := hotelService.cancellationTerms(context.Background(), rate, dto.Hotel{Currency: "EUR", DestinationCode: tt.destinationCode}, "EUR", now)
+
+			assert.NoError(t, err)
+			assert.True(t, terms.Refundable)
+			assert.Equal(t, tt.expectedFrom, terms.RefundableUntil)
+			assert.Equal(t, []dto.CancellationPenalty{{From: tt.expectedFrom, Amount: 80}}, terms.Penalties)
+		})
+	}
+}
+
+func TestSearchHotels_Boards(t *testing.T) {
+	tests := []struct {
+		name           string
+		boards         []dto.Board
+		expectedFilter *dto.BoardsFilter
+		expectedPrices map[string]float64
+		expectedBoards map[string]dto.Board
+	}{
+		{
+			name:           "Without filter",
+			expectedPrices: map[string]float64{"1234": 199.99, "5678": 299.99},
+			expectedBoards: map[string]dto.Board{"1234": dto.BoardRoomOnly, "5678": dto.BoardHalfBoard},
+		},
+		{
+			name:           "Breakfast variants are normalized",
+			boards:         []dto.Board{dto.BoardBedAndBreakfast},
+			expectedFilter: &dto.BoardsFilter{Board: []string{"AB", "BB", "CB"}, Included: true},
+			expectedPrices: map[string]float64{"1234": 229.99},
+			expectedBoards: map[string]dto.Board{"1234": dto.BoardBedAndBreakfast},
+		},
+		{
+			name:           "Several boards",
+			boards:         []dto.Board{dto.BoardHalfBoard, dto.BoardRoomOnly},
+			expectedFilter: &dto.BoardsFilter{Board: []string{"HB", "RO", "SC"}, Included: true},
+			expectedPrices: map[string]float64{"1234": 199.99, "5678": 299.99},
+			expectedBoards: map[string]dto.Board{"1234": dto.BoardRoomOnly, "5678": dto.BoardHalfBoard},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.MockHotelBedsClient{Boards: true}
+			hotelService := NewHotelService(client, &mocks.MockCurrencyService{}, nil, "")
+
+			result, err := hotelService.SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+				CheckIn:  "2024-12-25",
+				CheckOut: "2024-12-26",
+				HotelIDs: []string{"1234", "5678"},
+				Currency: "EUR",
+				Filters:  dto.RateFilters{Boards: tt.boards},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFilter, client.Requests[0].Boards)
+
+			prices := map[string]float64{}
+			boards := map[string]dto.Board{}
+			for _, price := range result.HotelPrices {
+				prices[price.HotelID] = price.Price
+				boards[price.HotelID] = price.Board
+			}
+			assert.Equal(t, tt.expectedPrices, prices)
+			assert.Equal(t, tt.expectedBoards, boards)
+		})
+	}
+}
+
+func TestSearchHotels_RateFilters(t *testing.T) {
+	packaged := true
+
+	tests := []struct {
+		name           string
+		filters        dto.RateFilters
+		markupPercent  float64
+		localOnly      bool
+		expectedFilter *dto.SearchFilter
+		expectedPrices map[string]float64
+	}{
+		{
+			name:           "Without filters",
+			expectedPrices: map[string]float64{"1234": 179.99, "5678": 269.99},
+		},
+		{
+			name:           "Price bounds apply to the converted price",
+			filters:        dto.RateFilters{MinPrice: 200, MaxPrice: 250},
+			expectedFilter: &dto.SearchFilter{MinRate: 222.21, MaxRate: 277.79},
+			expectedPrices: map[string]float64{"1234": 224.99},
+		},
+		{
+			name:           "Price bounds apply to the marked up price",
+			filters:        dto.RateFilters{MaxPrice: 250},
+			markupPercent:  10,
+			expectedFilter: &dto.SearchFilter{MaxRate: 252.54},
+			expectedPrices: map[string]float64{"1234": 197.99},
+		},
+		{
+			name:           "Price bounds are only checked locally without a supplier currency",
+			filters:        dto.RateFilters{MinPrice: 200, MaxPrice: 250},
+			localOnly:      true,
+			expectedPrices: map[string]float64{"1234": 224.99},
+		},
+		{
+			name:           "Payment type",
+			filters:        dto.RateFilters{PaymentType: dto.PaymentTypeAtHotel},
+			expectedFilter: &dto.SearchFilter{PaymentType: dto.PaymentTypeAtHotel},
+			expectedPrices: map[string]float64{"1234": 224.99, "5678": 269.99},
+		},
+		{
+			name:           "Packaging",
+			filters:        dto.RateFilters{Packaging: &packaged},
+			expectedFilter: &dto.SearchFilter{Packaging: &packaged},
+			expectedPrices: map[string]float64{"1234": 224.99},
+		},
+		{
+			name:           "Rate class",
+			filters:        dto.RateFilters{RateClass: dto.RateClassRefundable, MinPrice: 200},
+			expectedFilter: &dto.SearchFilter{MinRate: 222.21},
+			expectedPrices: map[string]float64{"5678": 269.99},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.MockHotelBedsClient{RateFilters: true}
+			supplierCurrency := "EUR"
+			if tt.localOnly {
+				supplierCurrency = ""
+			}
+			hotelService := NewHotelService(client, &mocks.MockCurrencyService{Rate: 0.9}, nil, supplierCurrency)
+
+			ctx := auth.WithTenant(context.Background(), auth.Tenant{ID: "acme", MarkupPercent: tt.markupPercent})
+			result, err := hotelService.SearchHotels(ctx, dto.HotelSearchServiceParams{
+				CheckIn:  "2024-12-25",
+				CheckOut: "2024-12-26",
+				HotelIDs: []string{"1234", "5678"},
+				Currency: "USD",
+				Filters:  tt.filters,
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedFilter, client.Requests[0].Filter)
+
+			assert.Len(t, result.HotelPrices, len(tt.expectedPrices))
+			for _, price := range result.HotelPrices {
+				assert.InDelta(t, tt.expectedPrices[price.HotelID], price.Price, 0.01, price.HotelID)
+			}
+		})
+	}
+}
+
+func TestSearchHotels_SearchModes(t *testing.T) {
+	tests := []struct {
+		name                string
+		params              dto.HotelSearchServiceParams
+		expectedRequest     dto.HotelBedsSearchRequest
+		expectedDistances   map[string]float64
+		expectedNoDistances bool
+	}{
+		{
+			name:                "Hotel list",
+			params:              dto.HotelSearchServiceParams{HotelIDs: []string{"1234", "5678"}},
+			expectedRequest:     dto.HotelBedsSearchRequest{Hotels: &dto.HotelsFilter{Hotel: []int{1234, 5678}}},
+			expectedNoDistances: true,
+		},
+		{
+			name:                "Destination",
+			params:              dto.HotelSearchServiceParams{Destination: "PMI"},
+			expectedRequest:     dto.HotelBedsSearchRequest{Destination: &dto.Destination{Code: "PMI"}},
+			expectedNoDistances: true,
+		},
+		{
+			name:              "Geolocation in kilometers",
+			params:            dto.HotelSearchServiceParams{Geolocation: &dto.Geolocation{Latitude: 39.5696, Longitude: 2.6502, Radius: 10, Unit: dto.UnitKilometers}},
+			expectedRequest:   dto.HotelBedsSearchRequest{Geolocation: &dto.Geolocation{Latitude: 39.5696, Longitude: 2.6502, Radius: 10, Unit: dto.UnitKilometers}},
+			expectedDistances: map[string]float64{"1234": 0, "5678": 5.44},
+		},
+		{
+			name:              "Geolocation in miles",
+			params:            dto.HotelSearchServiceParams{Geolocation: &dto.Geolocation{Latitude: 39.5696, Longitude: 2.6502, Radius: 5, Unit: dto.UnitMiles}},
+			expectedRequest:   dto.HotelBedsSearchRequest{Geolocation: &dto.Geolocation{Latitude: 39.5696, Longitude: 2.6502, Radius: 5, Unit: dto.UnitMiles}},
+			expectedDistances: map[string]float64{"1234": 0, "5678": 3.38},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.MockHotelBedsClient{}
+			hotelService := NewHotelService(client, &mocks.MockCurrencyService{}, nil, "")
+
+			params := tt.params
+			params.CheckIn, params.CheckOut, params.Currency = "2024-12-25", "2024-12-26", "EUR"
+			result, err := hotelService.SearchHotels(context.Background(), params)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedRequest.Hotels, client.Requests[0].Hotels)
+			assert.Equal(t, tt.expectedRequest.Destination, client.Requests[0].Destination)
+			assert.Equal(t, tt.expectedRequest.Geolocation, client.Requests[0].Geolocation)
+
+			for _, price := range result.HotelPrices {
+				if tt.expectedNoDistances {
+					assert.Nil(t, price.Distance)
+					continue
+				}
+				if assert.NotNil(t, price.Distance) {
+					assert.Equal(t, tt.expectedDistances[price.HotelID], *price.Distance)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchHotels_Sort(t *testing.T) {
+	tests := []struct {
+		name          string
+		sort          dto.SortOrder
+		hotelIDs      []string
+		geolocation   *dto.Geolocation
+		expectedOrder []string
+	}{
+		{name: "Supplier order", hotelIDs: []string{"1234", "5678"}, expectedOrder: []string{"1234", "5678"}},
+		{name: "Price ascending", sort: dto.SortPriceAsc, hotelIDs: []string{"1234", "5678"}, expectedOrder: []string{"1234", "5678"}},
+		{name: "Price descending", sort: dto.SortPriceDesc, hotelIDs: []string{"1234", "5678"}, expectedOrder: []string{"5678", "1234"}},
+		{name: "Hotel ID", sort: dto.SortHotelID, hotelIDs: []string{"5678", "1234"}, expectedOrder: []string{"1234", "5678"}},
+		{name: "Name", sort: dto.SortName, hotelIDs: []string{"1234", "5678"}, expectedOrder: []string{"5678", "1234"}},
+		{
+			name:          "Distance",
+			sort:          dto.SortDistance,
+			geolocation:   &dto.Geolocation{Latitude: 39.6, Longitude: 2.7, Radius: 20, Unit: dto.UnitKilometers},
+			expectedOrder: []string{"5678", "1234"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelService := NewHotelService(&mocks.MockHotelBedsClient{}, &mocks.MockCurrencyService{}, nil, "")
+
+			result, err := hotelService.SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+				CheckIn:     "2024-12-25",
+				CheckOut:    "2024-12-26",
+				HotelIDs:    tt.hotelIDs,
+				Geolocation: tt.geolocation,
+				Currency:    "EUR",
+				Sort:        tt.sort,
+			})
+
+			assert.NoError(t, err)
+
+			var order []string
+			for _, price := range result.HotelPrices {
+				order = append(order, price.HotelID)
+			}
+			assert.Equal(t, tt.expectedOrder, order)
+		})
+	}
+}
+
+func TestSortHotelPrices_HotelID(t *testing.T) {
+	prices := []dto.HotelPrice{{HotelID: "lp3c4d"}, {HotelID: "10"}, {HotelID: "lp1a2b"}, {HotelID: "2"}, {HotelID: "1a"}}
+
+	sortHotelPrices(prices, dto.SortHotelID)
+
+	var order []string
+	for _, price := range prices {
+		order = append(order, price.HotelID)
+	}
+	assert.Equal(t, []string{"2", "10", "1a", "lp1a2b", "lp3c4d"}, order)
+}
+
+func TestSearchHotels_IDMapping(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.csv")
+	assert.NoError(t, os.WriteFile(path, []byte("hotelId,supplier,code\nlp-zafiro,hotelbeds,1234\n"), 0o600))
+	mapper, err := idmap.NewFileMapper(path)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		hotelIDs         []string
+		expectedCodes    []int
+		expectedHotelIDs []string
+		expectedWarnings []string
+	}{
+		{
+			name:             "Mapped and unmapped IDs",
+			hotelIDs:         []string{"lp-zafiro", "lp-unknown"},
+			expectedCodes:    []int{1234},
+			expectedHotelIDs: []string{"lp-zafiro"},
+			expectedWarnings: []string{
+				`hotel id "lp-unknown" is not mapped to a hotelbeds hotel`,
+				"1 of the hotels found have no liteAPI hotel id and were left out",
+			},
+		},
+		{
+			name:             "Supplier codes are not hotel IDs",
+			hotelIDs:         []string{"1234"},
+			expectedWarnings: []string{`hotel id "1234" is not mapped to a hotelbeds hotel`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &mocks.MockHotelBedsClient{}
+			hotelService := NewHotelService(client, &mocks.MockCurrencyService{}, mapper, "")
+
+			result, err := hotelService.SearchHotels(context.Background(), dto.HotelSearchServiceParams{
+				CheckIn:  "2024-12-25",
+				CheckOut: "2024-12-26",
+				HotelIDs: tt.hotelIDs,
+				Currency: "EUR",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedWarnings, result.Warnings)
+
+			if tt.expectedCodes == nil {
+				assert.Zero(t, client.Calls, "nothing is searched without a mapped hotel")
+				return
+			}
+
+			assert.Equal(t, tt.expectedCodes, client.Requests[0].Hotels.Hotel)
+			var hotelIDs []string
+			for _, price := range result.HotelPrices {
+				hotelIDs = append(hotelIDs, price.HotelID)
+			}
+			assert.Equal(t, tt.expectedHotelIDs, hotelIDs)
+		})
+	}
+}
+
+func TestSearchHotels_FXPolicy(t *testing.T) {
+	// 199.99 EUR with the 10% markup is 219.989, which this rate turns into exactly 100.005 USD
+	halfRate := 100.005 / 219.989
+
+	tests := []struct {
+		name                   string
+		rate                   float64
+		currency               string
+		policy                 dto.ConversionOptions
+		expectedPrice          float64
+		expectedPerNight       float64
+		expectedChargePrice    float64
+		expectedChargeCurrency string
+	}{
+		{
+			name:                   "Charged in the requested currency",
+			rate:                   0.9,
+			currency:               "USD",
+			expectedPrice:          197.99,
+			expectedPerNight:       197.99,
+			expectedChargePrice:    197.99,
+			expectedChargeCurrency: "USD",
+		},
+		{
+			name:                   "Charged in the supplier currency",
+			rate:                   0.9,
+			currency:               "USD",
+			policy:                 dto.ConversionOptions{DisplayOnly: true},
+			expectedPrice:          197.99,
+			expectedPerNight:       197.99,
+			expectedChargePrice:    219.99,
+			expectedChargeCurrency: "EUR",
+		},
+		{
+			name:                   "Derived amounts rounded to whole yen",
+			rate:                   0.9,
+			currency:               "JPY",
+			expectedPrice:          197.99,
+			expectedPerNight:       198,
+			expectedChargePrice:    197.99,
+			expectedChargeCurrency: "JPY",
+		},
+		{
+			name:                   "Half-even rounding of derived amounts",
+			rate:                   halfRate,
+			currency:               "USD",
+			policy:                 dto.ConversionOptions{Rounding: config.RoundingHalfEven},
+			expectedPrice:          100.005,
+			expectedPerNight:       100.00,
+			expectedChargePrice:    100.005,
+			expectedChargeCurrency: "USD",
+		},
+		{
+			name:                   "Rounding up of derived amounts",
+			rate:                   halfRate,
+			currency:               "USD",
+			policy:                 dto.ConversionOptions{Rounding: config.RoundingUp},
+			expectedPrice:          100.005,
+			expectedPerNight:       100.01,
+			expectedChargePrice:    100.005,
+			expectedChargeCurrency: "USD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hotelService := NewHotelService(&mocks.MockHotelBedsClient{}, &mocks.MockCurrencyService{Rate: tt.rate, Policy: tt.policy}, nil, "")
+
+			ctx := auth.WithTenant(context.Background(), auth.Tenant{ID: "acme", MarkupPercent: 10})
+			result, err := hotelService.SearchHotels(ctx, dto.HotelSearchServiceParams{
+				CheckIn:  "2024-12-25",
+				CheckOut: "2024-12-26",
+				HotelIDs: []string{"1234"},
+				Currency: tt.currency,
+			})
+
+			assert.NoError(t, err)
+			price := result.HotelPrices[0]
+			assert.Equal(t, tt.currency, price.Currency)
+			assert.InDelta(t, tt.expectedPrice, price.Price, 0.01)
+			assert.Equal(t, tt.expectedPerNight, price.PerNight)
+			assert.Equal(t, 199.99, price.SupplierPrice)
+			assert.Equal(t, "EUR", price.SupplierCurrency)
+			assert.Equal(t, tt.rate, price.AppliedRate)
+			assert.InDelta(t, tt.expectedChargePrice, price.ChargePrice, 0.01)
+			assert.Equal(t, tt.expectedChargeCurrency, price.ChargeCurrency)
+		})
+	}
+}
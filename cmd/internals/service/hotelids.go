@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/idmap"
+)
+
+// supplierCodes translates liteAPI hotel IDs to Hotelbeds codes, in the requested order; IDs without a
+// Hotelbeds code are reported as warnings
+func supplierCodes(mapper idmap.Mapper, hotelIDs []string) (codes []int, warnings []string) {
+	for _, hotelID := range hotelIDs {
+		code, ok := mapper.SupplierCode(idmap.SupplierHotelbeds, hotelID)
+		if ok {
+			if n, err := strconv.Atoi(code); err == nil {
+				codes = append(codes, n)
+				continue
+			}
+		}
+
+		warnings = append(warnings, fmt.Sprintf("hotel id %q is not mapped to a hotelbeds hotel", hotelID))
+	}
+
+	return codes, warnings
+}
+
+// supplierCode translates a single liteAPI hotel ID to its Hotelbeds code
+func supplierCode(mapper idmap.Mapper, hotelID string) (int, bool) {
+	codes, _ := supplierCodes(mapper, []string{hotelID})
+	if len(codes) == 0 {
+		return 0, false
+	}
+
+	return codes[0], true
+}
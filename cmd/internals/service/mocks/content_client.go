@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+// Mock Content API client serving Portfolio in pages
+type MockContentClient struct {
+	ShouldError bool
+	Portfolio   []dto.ContentHotel
+	Calls       int
+}
+
+func (m *MockContentClient) Hotels(ctx context.Context, codes []int, from, to int) ([]byte, error) {
+	m.Calls++
+
+	if m.ShouldError {
+		return nil, fmt.Errorf("API error")
+	}
+
+	hotels := m.Portfolio
+	if len(codes) > 0 {
+		hotels = nil
+		for _, hotel := range m.Portfolio {
+			if slices.Contains(codes, hotel.Code) {
+				hotels = append(hotels, hotel)
+			}
+		}
+	}
+
+	response := dto.HotelbedsContentResponse{From: from, To: to, Total: len(hotels)}
+	if from <= len(hotels) {
+		response.Hotels = hotels[from-1 : min(to, len(hotels))]
+	}
+
+	return json.Marshal(response)
+}
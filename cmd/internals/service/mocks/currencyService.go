@@ -1,9 +1,18 @@
 package mocks
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
 
 type MockCurrencyService struct {
 	ShouldError bool
+	// Rate multiplies converted amounts; zero leaves them unchanged
+	Rate float64
+	// Policy is returned as the options of every currency pair
+	Policy dto.ConversionOptions
 }
 
 func (c *MockCurrencyService) Convert(amount float64, sourceCurr, targetCurr string) (float64, error) {
@@ -11,5 +20,75 @@ func (c *MockCurrencyService) Convert(amount float64, sourceCurr, targetCurr str
 		return amount, fmt.Errorf("Conversion error")
 	}
 
+	if c.Rate != 0 {
+		return amount * c.Rate, nil
+	}
+
 	return amount, nil
 }
+
+func (c *MockCurrencyService) RatesAsOf() time.Time {
+	return time.Now()
+}
+
+func (c *MockCurrencyService) Currencies() []dto.Currency {
+	return []dto.Currency{
+		{Code: "EUR", NumericCode: "978", Decimals: 2, Symbol: "€"},
+		{Code: "USD", NumericCode: "840", Decimals: 2, Symbol: "$"},
+	}
+}
+
+func (c *MockCurrencyService) Rates(base string) (map[string]float64, error) {
+	if c.ShouldError {
+		return nil, fmt.Errorf("Conversion error")
+	}
+
+	rates := map[string]float64{}
+	for _, currency := range c.Currencies() {
+		rates[currency.Code], _ = c.Convert(1, base, currency.Code)
+	}
+
+	return rates, nil
+}
+
+// ConvertWithOptions converts at Rate, or 1:1 within the same currency, and ignores the options
+func (c *MockCurrencyService) ConvertWithOptions(amount float64, sourceCurr, targetCurr string, opts dto.ConversionOptions) (dto.Conversion, error) {
+	if c.ShouldError {
+		return dto.Conversion{}, fmt.Errorf("Conversion error")
+	}
+
+	if c.Rate == 0 || sourceCurr == targetCurr {
+		return dto.Conversion{Amount: amount, Rate: 1}, nil
+	}
+
+	return dto.Conversion{Amount: amount * c.Rate, Rate: c.Rate}, nil
+}
+
+func (c *MockCurrencyService) Options(sourceCurr, targetCurr string) dto.ConversionOptions {
+	return c.Policy
+}
+
+// ConvertCurrent converts like Convert, without rounding
+func (c *MockCurrencyService) ConvertCurrent(amount float64, sourceCurr, targetCurr string) (dto.Conversion, error) {
+	rate, err := c.Convert(1, sourceCurr, targetCurr)
+	if err != nil {
+		return dto.Conversion{}, err
+	}
+
+	return dto.Conversion{Amount: amount * rate, Rate: rate, AsOf: c.RatesAsOf()}, nil
+}
+
+// ConvertAt converts like Convert, at the rate of the day of at
+func (c *MockCurrencyService) ConvertAt(amount float64, sourceCurr, targetCurr string, at time.Time) (dto.Conversion, error) {
+	rate, err := c.Convert(1, sourceCurr, targetCurr)
+	if err != nil {
+		return dto.Conversion{}, err
+	}
+
+	year, month, day := at.Date()
+	return dto.Conversion{Amount: amount * rate, Rate: rate, AsOf: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}, nil
+}
+
+func (c *MockCurrencyService) RateGaps(from, to time.Time) dto.RateGapReport {
+	return dto.RateGapReport{From: from.Format("2006-01-02"), To: to.Format("2006-01-02"), Days: 1, MissingDays: []string{}}
+}
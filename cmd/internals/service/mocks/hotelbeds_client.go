@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -12,9 +13,24 @@ type MockHotelBedsClient struct {
 	ShouldError     bool
 	InvalidRate     bool
 	InvalidResponse bool
+	DailyRates      bool
+	Taxes           bool
+	Cancellation    bool
+	Boards          bool
+	RateFilters     bool
+	// Requests holds the request of every call
+	Requests []dto.HotelBedsSearchRequest
+	Calls    int
 }
 
-func (m *MockHotelBedsClient) SearchHotels(request []byte) ([]byte, error) {
+func (m *MockHotelBedsClient) SearchHotels(ctx context.Context, request []byte) ([]byte, error) {
+	m.Calls++
+
+	var searchRequest dto.HotelBedsSearchRequest
+	if err := json.Unmarshal(request, &searchRequest); err == nil {
+		m.Requests = append(m.Requests, searchRequest)
+	}
+
 	if m.ShouldError {
 		return nil, fmt.Errorf("client error")
 	}
@@ -32,19 +48,129 @@ func (m *MockHotelBedsClient) SearchHotels(request []byte) ([]byte, error) {
 		Hotels: dto.Hotels{
 			Hotels: []dto.Hotel{
 				{
-					Code:     1234,
-					MinRate:  minRate,
-					Currency: "EUR",
+					Code:      1234,
+					Name:      "Zafiro Palace",
+					MinRate:   minRate,
+					Currency:  "EUR",
+					Latitude:  "39.5696",
+					Longitude: "2.6502",
 				},
 				{
-					Code:     5678,
-					MinRate:  "299.99",
-					Currency: "EUR",
+					Code:      5678,
+					Name:      "Almudaina",
+					MinRate:   "299.99",
+					Currency:  "EUR",
+					Latitude:  "39.6000",
+					Longitude: "2.7000",
 				},
 			},
 		},
 	}
 
+	// Hotel 1234 gets a pricier and a cheapest rate with a two night breakdown
+	if m.DailyRates {
+		result.Hotels.Hotels[0].Rooms = []dto.Room{
+			{
+				Code: "DBL.ST",
+				Rates: []dto.Rate{
+					{Net: "259.99", DailyRates: []dto.DailyRate{{Offset: 1, DailyNet: "129.99"}, {Offset: 2, DailyNet: "130.00"}}},
+					{Net: minRate, DailyRates: []dto.DailyRate{{Offset: 1, DailyNet: "99.99"}, {Offset: 2, DailyNet: "100.00"}}},
+				},
+			},
+		}
+	}
+
+	// Hotel 5678 gets a rate with an included tax and a city tax payable at the property in USD
+	if m.Taxes {
+		result.Hotels.Hotels[1].Rooms = []dto.Room{
+			{
+				Code: "DBL.ST",
+				Rates: []dto.Rate{
+					{
+						Net: "299.99",
+						Taxes: &dto.Taxes{
+							Taxes: []dto.Tax{
+								{Included: true, Amount: "27.27", Currency: "EUR", Type: "TAX"},
+								{Included: false, Amount: "10.00", Currency: "USD", Type: "TAXESANDFEES"},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// Hotel 1234, in Palma, gets a cheapest non-refundable rate and a pricier refundable one;
+	// hotel 5678 only gets a non-refundable rate
+	if m.Cancellation {
+		result.Hotels.Hotels[0].DestinationCode = "PMI"
+		result.Hotels.Hotels[0].Rooms = []dto.Room{
+			{
+				Code: "DBL.ST",
+				Rates: []dto.Rate{
+					{
+						Net:                  minRate,
+						RateClass:            dto.RateClassNonRefundable,
+						CancellationPolicies: []dto.CancellationPolicy{{Amount: minRate, From: "2024-01-01T00:00:00Z"}},
+					},
+					{
+						Net:       "249.99",
+						RateClass: "NOR",
+						CancellationPolicies: []dto.CancellationPolicy{
+							{Amount: "249.99", From: "2099-12-24T23:00:00Z"},
+							{Amount: "50.00", From: "2099-12-20T23:00:00Z"},
+						},
+					},
+				},
+			},
+		}
+		result.Hotels.Hotels[1].Rooms = []dto.Room{
+			{
+				Code:  "DBL.ST",
+				Rates: []dto.Rate{{Net: "299.99", RateClass: dto.RateClassNonRefundable}},
+			},
+		}
+	}
+
+	// Hotel 1234 gets a cheapest room only rate and a bed and breakfast one; hotel 5678 only gets half board
+	if m.Boards {
+		result.Hotels.Hotels[0].Rooms = []dto.Room{
+			{
+				Code: "DBL.ST",
+				Rates: []dto.Rate{
+					{Net: minRate, BoardCode: "RO", BoardName: "ROOM ONLY"},
+					{Net: "229.99", BoardCode: "CB", BoardName: "CONTINENTAL BREAKFAST"},
+				},
+			},
+		}
+		result.Hotels.Hotels[1].Rooms = []dto.Room{
+			{
+				Code:  "DBL.ST",
+				Rates: []dto.Rate{{Net: "299.99", BoardCode: "HB", BoardName: "HALF BOARD"}},
+			},
+		}
+	}
+
+	// Hotel 1234 gets a cheapest refundable rate paid online and a packaged non-refundable one paid at the
+	// hotel; hotel 5678 gets a refundable rate paid at the hotel
+	if m.RateFilters {
+		result.Hotels.Hotels[0].Rooms = []dto.Room{
+			{
+				Code: "DBL.ST",
+				Rates: []dto.Rate{
+					{Net: minRate, RateClass: dto.RateClassRefundable, PaymentType: dto.PaymentTypeAtWeb},
+					{Net: "249.99", RateClass: dto.RateClassNonRefundable, PaymentType: dto.PaymentTypeAtHotel, Packaging: true},
+				},
+			},
+		}
+		result.Hotels.Hotels[1].Rooms = []dto.Room{
+			{
+				Code:  "DBL.ST",
+				Rates: []dto.Rate{{Net: "299.99", RateClass: dto.RateClassRefundable, PaymentType: dto.PaymentTypeAtHotel}},
+			},
+		}
+	}
+
 	jsonResult, err := json.Marshal(result)
 	if err != nil {
 		return nil, err
@@ -52,3 +178,11 @@ func (m *MockHotelBedsClient) SearchHotels(request []byte) ([]byte, error) {
 
 	return jsonResult, nil
 }
+
+func (m *MockHotelBedsClient) Status(ctx context.Context) error {
+	if m.ShouldError {
+		return fmt.Errorf("client error")
+	}
+
+	return nil
+}
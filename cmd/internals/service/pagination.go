@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/cache"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+// ErrInvalidPage is returned for a page limit out of bounds or a cursor that is malformed, tampered with,
+// expired or issued to another tenant
+var ErrInvalidPage = errors.New("invalid page")
+
+// SearchPaginator pages search responses. The first page stores the whole response, so the following
+// pages are served from it without searching the supplier again.
+type SearchPaginator interface {
+	FirstPage(ctx context.Context, response dto.HotelPriceResponse, limit int) (dto.HotelPriceResponse, error)
+	NextPage(ctx context.Context, cursor string, limit int) (dto.HotelPriceResponse, error)
+}
+
+type SearchPaginatorImpl struct {
+	results     cache.Cache
+	secret      []byte
+	ttl         time.Duration
+	maxPageSize int
+	now         func() time.Time
+}
+
+// cursor is the signed position in a stored search; Limit is reused when the next request sets none
+type cursor struct {
+	SearchID  string `json:"s"`
+	Offset    int    `json:"o"`
+	Limit     int    `json:"l"`
+	TenantID  string `json:"t,omitempty"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// NewSearchPaginator creates the paginator; without a configured secret cursors are signed with a random
+// one and do not survive a restart, like the stored searches they point to
+func NewSearchPaginator(cfg config.SearchConfig) SearchPaginator {
+	secret := []byte(cfg.CursorSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Sprintf("failed to generate cursor secret: %v", err))
+		}
+	}
+
+	return &SearchPaginatorImpl{
+		results:     cache.NewMemoryCache(cfg.CursorTTL.Duration, cfg.MaxPagedSearches),
+		secret:      secret,
+		ttl:         cfg.CursorTTL.Duration,
+		maxPageSize: cfg.MaxPageSize,
+		now:         time.Now,
+	}
+}
+
+// FirstPage stores the response and returns its first limit hotels, with the supplier payloads and warnings
+func (p *SearchPaginatorImpl) FirstPage(ctx context.Context, response dto.HotelPriceResponse, limit int) (dto.HotelPriceResponse, error) {
+	if err := p.validateLimit(limit); err != nil {
+		return dto.HotelPriceResponse{}, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return dto.HotelPriceResponse{}, fmt.Errorf("failed to generate search ID: %w", err)
+	}
+
+	stored, err := json.Marshal(response.Data)
+	if err != nil {
+		return dto.HotelPriceResponse{}, fmt.Errorf("failed to store search: %w", err)
+	}
+
+	c := cursor{
+		SearchID:  hex.EncodeToString(id),
+		Limit:     limit,
+		TenantID:  tenantID(ctx),
+		ExpiresAt: p.now().Add(p.ttl).Unix(),
+	}
+	p.results.Set(c.SearchID, stored)
+	slog.DebugContext(ctx, "stored paged search", slog.String("searchId", c.SearchID), slog.Int("hotels", len(response.Data)))
+
+	page, err := p.page(c, response.Data)
+	if err != nil {
+		return dto.HotelPriceResponse{}, err
+	}
+	page.Supplier = response.Supplier
+	page.Warnings = response.Warnings
+
+	return page, nil
+}
+
+// NextPage returns the page the cursor points to; a positive limit overrides the cursor's
+func (p *SearchPaginatorImpl) NextPage(ctx context.Context, encoded string, limit int) (dto.HotelPriceResponse, error) {
+	c, err := p.decode(encoded)
+	if err != nil {
+		return dto.HotelPriceResponse{}, err
+	}
+
+	if c.TenantID != tenantID(ctx) {
+		return dto.HotelPriceResponse{}, fmt.Errorf("%w: cursor was issued to another tenant", ErrInvalidPage)
+	}
+
+	if p.now().Unix() > c.ExpiresAt {
+		return dto.HotelPriceResponse{}, fmt.Errorf("%w: cursor expired", ErrInvalidPage)
+	}
+
+	if limit > 0 {
+		if err := p.validateLimit(limit); err != nil {
+			return dto.HotelPriceResponse{}, err
+		}
+		c.Limit = limit
+	}
+
+	stored, ok := p.results.Get(c.SearchID)
+	if !ok {
+		return dto.HotelPriceResponse{}, fmt.Errorf("%w: cursor expired", ErrInvalidPage)
+	}
+
+	var data []dto.HotelPrice
+	if err := json.Unmarshal(stored, &data); err != nil {
+		return dto.HotelPriceResponse{}, fmt.Errorf("failed to read stored search: %w", err)
+	}
+
+	return p.page(c, data)
+}
+
+func (p *SearchPaginatorImpl) validateLimit(limit int) error {
+	if limit < 1 || limit > p.maxPageSize {
+		return fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, p.maxPageSize)
+	}
+
+	return nil
+}
+
+// page slices the data at the cursor and signs the cursor of the following page, if any
+func (p *SearchPaginatorImpl) page(c cursor, data []dto.HotelPrice) (dto.HotelPriceResponse, error) {
+	start := min(c.Offset, len(data))
+	end := min(start+c.Limit, len(data))
+
+	page := dto.HotelPriceResponse{Data: data[start:end]}
+	if page.Data == nil {
+		page.Data = []dto.HotelPrice{}
+	}
+
+	if end < len(data) {
+		next := c
+		next.Offset = end
+
+		encoded, err := p.encode(next)
+		if err != nil {
+			return dto.HotelPriceResponse{}, err
+		}
+		page.NextCursor = encoded
+	}
+
+	return page, nil
+}
+
+// encode serializes the cursor as base64url(payload).base64url(hmac)
+func (p *SearchPaginatorImpl) encode(c cursor) (string, error) {
+	payload, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(p.sign(payload)), nil
+}
+
+func (p *SearchPaginatorImpl) decode(encoded string) (cursor, error) {
+	var c cursor
+
+	payloadPart, signaturePart, ok := strings.Cut(encoded, ".")
+	if !ok {
+		return c, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadPart)
+	if err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(signaturePart)
+	if err != nil || !hmac.Equal(signature, p.sign(payload)) {
+		return c, fmt.Errorf("%w: cursor signature mismatch", ErrInvalidPage)
+	}
+
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return c, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+
+	return c, nil
+}
+
+func (p *SearchPaginatorImpl) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// tenantID binds cursors to the authenticated tenant; it is empty when authentication is disabled
+func tenantID(ctx context.Context) string {
+	if tenant, ok := auth.TenantFromContext(ctx); ok {
+		return tenant.ID
+	}
+
+	return ""
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func pagedResponse(hotels int) dto.HotelPriceResponse {
+	response := dto.HotelPriceResponse{Supplier: dto.Supplier{Request: "request", Response: "response"}}
+	for i := 0; i < hotels; i++ {
+		response.Data = append(response.Data, dto.HotelPrice{HotelID: string(rune('A' + i)), Currency: "EUR", Price: float64(100 + i)})
+	}
+
+	return response
+}
+
+func newTestPaginator(secret string) *SearchPaginatorImpl {
+	cfg := config.Default().Search
+	cfg.CursorSecret = secret
+	cfg.MaxPageSize = 3
+
+	return NewSearchPaginator(cfg).(*SearchPaginatorImpl)
+}
+
+func TestSearchPaginator_Pages(t *testing.T) {
+	paginator := newTestPaginator("secret")
+	ctx := context.Background()
+
+	page, err := paginator.FirstPage(ctx, pagedResponse(5), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "request", page.Supplier.Request)
+
+	ids := []string{}
+	pages := 1
+	for {
+		for _, price := range page.Data {
+			ids = append(ids, price.HotelID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+
+		page, err = paginator.NextPage(ctx, page.NextCursor, 0)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Supplier.Request)
+		pages++
+	}
+
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"A", "B", "C", "D", "E"}, ids)
+}
+
+func TestSearchPaginator_LimitOverride(t *testing.T) {
+	paginator := newTestPaginator("secret")
+	ctx := context.Background()
+
+	page, err := paginator.FirstPage(ctx, pagedResponse(5), 1)
+	assert.NoError(t, err)
+
+	page, err = paginator.NextPage(ctx, page.NextCursor, 3)
+	assert.NoError(t, err)
+	assert.Len(t, page.Data, 3)
+	assert.Equal(t, "B", page.Data[0].HotelID)
+	assert.NotEmpty(t, page.NextCursor)
+}
+
+func TestSearchPaginator_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		limit       int
+		nextLimit   int
+		cursor      func(cursor string) string
+		nextCtx     context.Context
+		advance     time.Duration
+		paginator   *SearchPaginatorImpl
+		errContains string
+	}{
+		{name: "Limit too large", limit: 4, errContains: "limit must be between 1 and 3"},
+		{name: "Negative limit", limit: -1, errContains: "limit must be between 1 and 3"},
+		{name: "Next limit too large", limit: 1, nextLimit: 10, errContains: "limit must be between 1 and 3"},
+		{
+			name:  "Tampered cursor",
+			limit: 1,
+			cursor: func(cursor string) string {
+				return "eyJzIjoieCIsIm8iOjAsImwiOjEsImUiOjk5OTk5OTk5OTl9" + cursor[strings.Index(cursor, "."):]
+			},
+			errContains: "cursor signature mismatch",
+		},
+		{name: "Malformed cursor", limit: 1, cursor: func(string) string { return "not-a-cursor" }, errContains: "malformed cursor"},
+		{name: "Signed with another secret", limit: 1, paginator: newTestPaginator("other"), errContains: "cursor signature mismatch"},
+		{
+			name:        "Another tenant",
+			limit:       1,
+			nextCtx:     auth.WithTenant(context.Background(), auth.Tenant{ID: "acme"}),
+			errContains: "cursor was issued to another tenant",
+		},
+		{name: "Expired", limit: 1, advance: time.Hour, errContains: "cursor expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paginator := newTestPaginator("secret")
+			ctx := context.Background()
+
+			page, err := paginator.FirstPage(ctx, pagedResponse(3), tt.limit)
+			if err == nil {
+				cursor := page.NextCursor
+				if tt.cursor != nil {
+					cursor = tt.cursor(cursor)
+				}
+
+				next := paginator
+				if tt.paginator != nil {
+					next = tt.paginator
+				}
+
+				nextCtx := ctx
+				if tt.nextCtx != nil {
+					nextCtx = tt.nextCtx
+				}
+
+				start := time.Now()
+				next.now = func() time.Time { return start.Add(tt.advance) }
+
+				_, err = next.NextPage(nextCtx, cursor, tt.nextLimit)
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidPage)
+			assert.ErrorContains(t, err, tt.errContains)
+		})
+	}
+}
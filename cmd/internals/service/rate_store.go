@@ -0,0 +1,283 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Rhymond/go-money"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+// ErrRateNotFound is returned when no rate of the currency pair is known on or before the requested day
+var ErrRateNotFound = errors.New("no exchange rate")
+
+// RateStore keeps exchange rates by day. Rates are quoted as units of a currency per unit of a base
+// currency; a pair is resolved directly, inverted, or crossed through a base quoting both currencies.
+type RateStore interface {
+	// Rate returns the rate from one currency to another of the latest day, on or before the date of at,
+	// that has one, and that day
+	Rate(from, to string, at time.Time) (float64, time.Time, error)
+	// Convert converts amount at the rate Rate resolves
+	Convert(amount float64, from, to string, at time.Time) (float64, error)
+	// Load adds the rates of a CSV file with a date,base,currency,rate header and returns how many it read.
+	// A file with an invalid row is rejected whole; rates already stored for the same day are replaced.
+	Load(r io.Reader) (int, error)
+	// Gaps reports the days between from and to, both included, without any rate
+	Gaps(from, to time.Time) dto.RateGapReport
+	// LoadedAt returns when rates were last loaded, or the zero time when none are
+	LoadedAt() time.Time
+}
+
+// dayRates holds the rates of a day: quotes per currency, by base currency
+type dayRates map[string]map[string]float64
+
+type RateStoreImpl struct {
+	mu   sync.RWMutex
+	days map[time.Time]dayRates
+	// sorted lists the days with rates in ascending order
+	sorted   []time.Time
+	loadedAt time.Time
+}
+
+func NewRateStore() RateStore {
+	return &RateStoreImpl{
+		days: make(map[time.Time]dayRates),
+	}
+}
+
+func (s *RateStoreImpl) Rate(from, to string, at time.Time) (float64, time.Time, error) {
+	for _, code := range []string{from, to} {
+		if money.GetCurrency(code) == nil {
+			return 0, time.Time{}, fmt.Errorf("%w: %v", ErrUnknownCurrency, code)
+		}
+	}
+
+	day := dayOf(at)
+	if from == to {
+		return 1, day, nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Walk back from the last day on or before the requested one until a day quotes the pair
+	i := sort.Search(len(s.sorted), func(i int) bool { return s.sorted[i].After(day) })
+	for i--; i >= 0; i-- {
+		if rate, ok := s.days[s.sorted[i]].rate(from, to); ok {
+			return rate, s.sorted[i], nil
+		}
+	}
+
+	return 0, time.Time{}, fmt.Errorf("%w from %v to %v on or before %v", ErrRateNotFound, from, to, day.Format(dateLayout))
+}
+
+func (s *RateStoreImpl) Convert(amount float64, from, to string, at time.Time) (float64, error) {
+	rate, _, err := s.Rate(from, to, at)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * rate, nil
+}
+
+func (s *RateStoreImpl) Load(r io.Reader) (int, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return 0, fmt.Errorf("missing header: %w", err)
+	}
+	if strings.Join(header, ",") != "date,base,currency,rate" {
+		return 0, errors.New("header must be date,base,currency,rate")
+	}
+
+	loaded := make(map[time.Time]dayRates)
+	count := 0
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		day, err := time.Parse(dateLayout, record[0])
+		if err != nil {
+			return 0, fmt.Errorf("line %d: date must be in format YYYY-MM-DD, got %q", line, record[0])
+		}
+
+		base, currency := strings.ToUpper(record[1]), strings.ToUpper(record[2])
+		for _, code := range []string{base, currency} {
+			if money.GetCurrency(code) == nil {
+				return 0, fmt.Errorf("line %d: %w: %v", line, ErrUnknownCurrency, code)
+			}
+		}
+
+		rate, err := strconv.ParseFloat(record[3], 64)
+		if err != nil || rate <= 0 {
+			return 0, fmt.Errorf("line %d: rate must be a positive number, got %q", line, record[3])
+		}
+
+		if loaded[day] == nil {
+			loaded[day] = dayRates{}
+		}
+		if loaded[day][base] == nil {
+			loaded[day][base] = map[string]float64{}
+		}
+		loaded[day][base][currency] = rate
+		count++
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for day, rates := range loaded {
+		if s.days[day] == nil {
+			s.days[day] = dayRates{}
+			s.sorted = append(s.sorted, day)
+		}
+		for base, quotes := range rates {
+			if s.days[day][base] == nil {
+				s.days[day][base] = map[string]float64{}
+			}
+			for currency, rate := range quotes {
+				s.days[day][base][currency] = rate
+			}
+		}
+	}
+	sort.Slice(s.sorted, func(i, j int) bool { return s.sorted[i].Before(s.sorted[j]) })
+	s.loadedAt = time.Now()
+
+	return count, nil
+}
+
+func (s *RateStoreImpl) Gaps(from, to time.Time) dto.RateGapReport {
+	from, to = dayOf(from), dayOf(to)
+	report := dto.RateGapReport{
+		From:        from.Format(dateLayout),
+		To:          to.Format(dateLayout),
+		MissingDays: []string{},
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		report.Days++
+		if _, ok := s.days[day]; !ok {
+			report.MissingDays = append(report.MissingDays, day.Format(dateLayout))
+		}
+	}
+
+	return report
+}
+
+func (s *RateStoreImpl) LoadedAt() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.loadedAt
+}
+
+// rate resolves the pair from the quotes of the day, preferring a direct quote, then an inverted one,
+// then a cross rate through the first base, in code order, quoting both currencies
+func (d dayRates) rate(from, to string) (float64, bool) {
+	if rate, ok := d[from][to]; ok {
+		return rate, true
+	}
+	if rate, ok := d[to][from]; ok {
+		return 1 / rate, true
+	}
+
+	bases := make([]string, 0, len(d))
+	for base := range d {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+
+	for _, base := range bases {
+		fromRate, okFrom := d[base][from]
+		toRate, okTo := d[base][to]
+		if okFrom && okTo {
+			return toRate / fromRate, true
+		}
+	}
+
+	return 0, false
+}
+
+// dayOf returns the calendar day of t, in the location of t, as the UTC midnight the store is keyed by
+func dayOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// LoadRateFiles loads every .csv file in dir into store in name order, so later files win for the same day.
+// The files are all checked first, so an invalid file leaves store untouched.
+func LoadRateFiles(store RateStore, dir string) (int, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(files)
+
+	contents := make([][]byte, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read rate file: %w", err)
+		}
+
+		if _, err := NewRateStore().Load(bytes.NewReader(content)); err != nil {
+			return 0, fmt.Errorf("failed to load rate file %v: %w", file, err)
+		}
+		contents = append(contents, content)
+	}
+
+	total := 0
+	for _, content := range contents {
+		count, err := store.Load(bytes.NewReader(content))
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+// WatchRateFiles loads the rate files of dir into store again every interval, until ctx is done
+func WatchRateFiles(ctx context.Context, store RateStore, dir string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		rates, err := LoadRateFiles(store, dir)
+		if err != nil {
+			slog.ErrorContext(ctx, "historical rates reload failed, keeping the previous rates", slog.String("error", err.Error()))
+			continue
+		}
+		slog.InfoContext(ctx, "historical rates reloaded", slog.Int("rates", rates))
+	}
+}
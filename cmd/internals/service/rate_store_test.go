@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const historicalRates = `date,base,currency,rate
+2024-01-01,EUR,USD,1.10
+2024-01-01,EUR,GBP,0.86
+2024-01-03,EUR,USD,1.20
+2024-01-04,USD,JPY,140
+`
+
+func newLoadedRateStore(t *testing.T) RateStore {
+	store := NewRateStore()
+	count, err := store.Load(strings.NewReader(historicalRates))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	return store
+}
+
+func TestRateStore_Rate(t *testing.T) {
+	store := newLoadedRateStore(t)
+
+	tests := []struct {
+		name          string
+		from          string
+		to            string
+		at            time.Time
+		expectedRate  float64
+		expectedDay   string
+		expectedError error
+	}{
+		{
+			name:         "Rate of the day",
+			from:         "EUR",
+			to:           "USD",
+			at:           time.Date(2024, 1, 3, 18, 0, 0, 0, time.UTC),
+			expectedRate: 1.20,
+			expectedDay:  "2024-01-03",
+		},
+		{
+			name:         "Nearest prior day",
+			from:         "EUR",
+			to:           "USD",
+			at:           time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			expectedRate: 1.10,
+			expectedDay:  "2024-01-01",
+		},
+		{
+			name:         "Day without the pair falls back further",
+			from:         "EUR",
+			to:           "GBP",
+			at:           time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC),
+			expectedRate: 0.86,
+			expectedDay:  "2024-01-01",
+		},
+		{
+			name:         "Inverted",
+			from:         "USD",
+			to:           "EUR",
+			at:           time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			expectedRate: 1 / 1.20,
+			expectedDay:  "2024-01-03",
+		},
+		{
+			name:         "Cross rate",
+			from:         "USD",
+			to:           "GBP",
+			at:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedRate: 0.86 / 1.10,
+			expectedDay:  "2024-01-01",
+		},
+		{
+			name:         "Date in the location of at",
+			from:         "EUR",
+			to:           "USD",
+			at:           time.Date(2024, 1, 3, 1, 0, 0, 0, time.FixedZone("CET", 3600)),
+			expectedRate: 1.20,
+			expectedDay:  "2024-01-03",
+		},
+		{
+			name:          "Before the first day",
+			from:          "EUR",
+			to:            "USD",
+			at:            time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC),
+			expectedError: ErrRateNotFound,
+		},
+		{
+			name:          "Unknown currency",
+			from:          "EUR",
+			to:            "ABC",
+			at:            time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			expectedError: ErrUnknownCurrency,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, day, err := store.Rate(tt.from, tt.to, tt.at)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, tt.expectedRate, rate, 1e-12)
+			assert.Equal(t, tt.expectedDay, day.Format(dateLayout))
+		})
+	}
+}
+
+func TestRateStore_Convert(t *testing.T) {
+	store := newLoadedRateStore(t)
+
+	converted, err := store.Convert(100, "EUR", "USD", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.InDelta(t, 110, converted, 1e-9)
+}
+
+func TestRateStore_Load(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "Wrong header",
+			content:       "day,base,currency,rate\n2024-01-01,EUR,USD,1.1\n",
+			expectedError: "header must be date,base,currency,rate",
+		},
+		{
+			name:          "Invalid date",
+			content:       "date,base,currency,rate\n01/01/2024,EUR,USD,1.1\n",
+			expectedError: "line 2: date must be in format YYYY-MM-DD",
+		},
+		{
+			name:          "Unknown currency",
+			content:       "date,base,currency,rate\n2024-01-01,EUR,ABC,1.1\n",
+			expectedError: "line 2: failed to find Currency by code: ABC",
+		},
+		{
+			name:          "Invalid rate",
+			content:       "date,base,currency,rate\n2024-01-01,EUR,USD,1.1\n2024-01-02,EUR,USD,0\n",
+			expectedError: "line 3: rate must be a positive number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewRateStore()
+
+			_, err := store.Load(strings.NewReader(tt.content))
+			assert.ErrorContains(t, err, tt.expectedError)
+
+			// A rejected file adds none of its rates
+			assert.Len(t, store.Gaps(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).MissingDays, 1)
+		})
+	}
+}
+
+func TestRateStore_Gaps(t *testing.T) {
+	store := newLoadedRateStore(t)
+
+	report := store.Gaps(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, "2023-12-31", report.From)
+	assert.Equal(t, "2024-01-05", report.To)
+	assert.Equal(t, 6, report.Days)
+	assert.Equal(t, []string{"2023-12-31", "2024-01-02", "2024-01-05"}, report.MissingDays)
+}
+
+func TestRateStore_LoadedAt(t *testing.T) {
+	assert.True(t, NewRateStore().LoadedAt().IsZero())
+
+	before := time.Now()
+	assert.False(t, newLoadedRateStore(t).LoadedAt().Before(before))
+}
+
+func TestLoadRateFiles(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2024-01.csv"), []byte("date,base,currency,rate\n2024-01-01,EUR,USD,1.10\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2024-02.csv"), []byte("date,base,currency,rate\n2024-01-01,EUR,USD,1.15\n2024-02-01,EUR,USD,1.12\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not rates"), 0o644))
+
+	store := NewRateStore()
+	count, err := LoadRateFiles(store, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// Later files win for the same day
+	rate, _, err := store.Rate("EUR", "USD", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Equal(t, 1.15, rate)
+
+	// An invalid file leaves the store untouched, even the rates of the valid files before it
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2024-03.csv"), []byte("date,base,currency,rate\nbad\n"), 0o644))
+	unloaded := NewRateStore()
+	_, err = LoadRateFiles(unloaded, dir)
+	assert.ErrorContains(t, err, "2024-03.csv")
+	assert.True(t, unloaded.LoadedAt().IsZero())
+	_, _, err = unloaded.Rate("EUR", "USD", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrRateNotFound)
+}
+
+func TestWatchRateFiles(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2024-01.csv"), []byte("date,base,currency,rate\n2024-01-01,EUR,USD,1.10\n"), 0o644))
+
+	store := NewRateStore()
+	_, err := LoadRateFiles(store, dir)
+	assert.NoError(t, err)
+	loadedAt := store.LoadedAt()
+
+	go WatchRateFiles(ctx, store, dir, 5*time.Millisecond)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2024-02.csv"), []byte("date,base,currency,rate\n2024-02-01,EUR,USD,1.12\n"), 0o644))
+	assert.Eventually(t, func() bool {
+		rate, _, err := store.Rate("EUR", "USD", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+		return err == nil && rate == 1.12
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, store.LoadedAt().After(loadedAt))
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var (
+	// ErrJobNotFound is returned for a job that never existed, expired or belongs to another tenant
+	ErrJobNotFound = errors.New("search job not found")
+	// ErrJobsBusy is returned when the queue or the store cannot take another job
+	ErrJobsBusy = errors.New("too many search jobs, retry later")
+)
+
+// JobStore keeps search jobs until their ExpiresAt
+type JobStore interface {
+	// Create stores a new job; it returns ErrJobsBusy when the store is full
+	Create(job dto.SearchJob) error
+	Get(id string) (dto.SearchJob, bool)
+	// Update applies update to the stored job atomically and reports false when the job is gone
+	Update(id string, update func(job *dto.SearchJob)) bool
+}
+
+type MemoryJobStore struct {
+	mu      sync.Mutex
+	jobs    map[string]dto.SearchJob
+	maxJobs int
+	now     func() time.Time
+}
+
+func NewMemoryJobStore(maxJobs int) *MemoryJobStore {
+	return &MemoryJobStore{
+		jobs:    map[string]dto.SearchJob{},
+		maxJobs: maxJobs,
+		now:     time.Now,
+	}
+}
+
+// Create drops the expired jobs when the store is full before giving up
+func (s *MemoryJobStore) Create(job dto.SearchJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.jobs) >= s.maxJobs {
+		now := s.now()
+		for id, stored := range s.jobs {
+			if now.After(stored.ExpiresAt) {
+				delete(s.jobs, id)
+			}
+		}
+	}
+	if len(s.jobs) >= s.maxJobs {
+		return ErrJobsBusy
+	}
+
+	s.jobs[job.ID] = job
+
+	return nil
+}
+
+// Get returns the job; its data is shared with the stored job, which updates only ever replace
+func (s *MemoryJobStore) Get(id string) (dto.SearchJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.live(id)
+}
+
+func (s *MemoryJobStore) Update(id string, update func(job *dto.SearchJob)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.live(id)
+	if !ok {
+		return false
+	}
+
+	update(&job)
+	s.jobs[id] = job
+
+	return true
+}
+
+// live returns the job unless it expired, deleting it if it did; the caller holds the lock
+func (s *MemoryJobStore) live(id string) (dto.SearchJob, bool) {
+	job, ok := s.jobs[id]
+	if !ok {
+		return job, false
+	}
+
+	if s.now().After(job.ExpiresAt) {
+		delete(s.jobs, id)
+		return dto.SearchJob{}, false
+	}
+
+	return job, true
+}
+
+// SearchJobService runs hotel searches in the background, in batches of hotels, so long searches are
+// polled for instead of held open
+type SearchJobService interface {
+	Submit(ctx context.Context, params dto.HotelSearchServiceParams) (dto.SearchJob, error)
+	Job(ctx context.Context, id string) (dto.SearchJob, error)
+	// Run searches the queued batches with the worker pool until ctx is done
+	Run(ctx context.Context)
+}
+
+// jobBatch is one search of a job, queued for the worker pool
+type jobBatch struct {
+	ctx   context.Context
+	jobID string
+	// index is the position of the batch in the job
+	index  int
+	params dto.HotelSearchServiceParams
+}
+
+type SearchJobServiceImpl struct {
+	hotelService HotelService
+	store        JobStore
+	// mu makes checking the queue room and queueing the batches of a job atomic
+	mu        sync.Mutex
+	queue     chan jobBatch
+	workers   int
+	batchSize int
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+// NewSearchJobService creates the job service; jobs are only searched once Run is started
+func NewSearchJobService(hotelService HotelService, store JobStore, cfg config.SearchConfig) SearchJobService {
+	return &SearchJobServiceImpl{
+		hotelService: hotelService,
+		store:        store,
+		queue:        make(chan jobBatch, cfg.JobQueueSize),
+		workers:      cfg.JobWorkers,
+		batchSize:    cfg.JobBatchSize,
+		ttl:          cfg.JobTTL.Duration,
+		now:          time.Now,
+	}
+}
+
+// Submit stores the job and queues its batches. The batches keep the request's values, such as the tenant
+// and the trace, but not its cancellation, as they outlive the request.
+func (s *SearchJobServiceImpl) Submit(ctx context.Context, params dto.HotelSearchServiceParams) (dto.SearchJob, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return dto.SearchJob{}, fmt.Errorf("failed to generate job ID: %w", err)
+	}
+
+	batches := s.batches(params)
+	now := s.now()
+	job := dto.SearchJob{
+		ID:        hex.EncodeToString(id),
+		Status:    dto.JobQueued,
+		Batches:   len(batches),
+		Data:      []dto.HotelPrice{},
+		BatchData: make([][]dto.HotelPrice, len(batches)),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+		TenantID:  tenantID(ctx),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cap(s.queue)-len(s.queue) < len(batches) {
+		return dto.SearchJob{}, ErrJobsBusy
+	}
+
+	if err := s.store.Create(job); err != nil {
+		return dto.SearchJob{}, err
+	}
+
+	background := context.WithoutCancel(ctx)
+	for i, batch := range batches {
+		s.queue <- jobBatch{ctx: background, jobID: job.ID, index: i, params: batch}
+	}
+	slog.InfoContext(ctx, "search job queued", slog.String("jobId", job.ID), slog.Int("batches", len(batches)))
+
+	return job, nil
+}
+
+func (s *SearchJobServiceImpl) Job(ctx context.Context, id string) (dto.SearchJob, error) {
+	job, ok := s.store.Get(id)
+	if !ok || job.TenantID != tenantID(ctx) {
+		return dto.SearchJob{}, fmt.Errorf("%w: %v", ErrJobNotFound, id)
+	}
+
+	return job, nil
+}
+
+func (s *SearchJobServiceImpl) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case batch := <-s.queue:
+					s.search(batch)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// batches splits a hotel list search into searches of at most batchSize hotels; destination and
+// geolocation searches are a single batch
+func (s *SearchJobServiceImpl) batches(params dto.HotelSearchServiceParams) []dto.HotelSearchServiceParams {
+	if len(params.HotelIDs) <= s.batchSize {
+		return []dto.HotelSearchServiceParams{params}
+	}
+
+	var batches []dto.HotelSearchServiceParams
+	for start := 0; start < len(params.HotelIDs); start += s.batchSize {
+		batch := params
+		batch.HotelIDs = params.HotelIDs[start:min(start+s.batchSize, len(params.HotelIDs))]
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// search runs one batch and merges its hotels into the job; a failed batch is reported as a warning
+func (s *SearchJobServiceImpl) search(batch jobBatch) {
+	ctx, span := telemetry.StartSpan(batch.ctx, "SearchJobService.search")
+	span.SetAttributes(attribute.String("job.id", batch.jobID), attribute.Int("hotels.requested", len(batch.params.HotelIDs)))
+	defer span.End()
+
+	// A job that expired while queued is not searched
+	running := s.store.Update(batch.jobID, func(job *dto.SearchJob) {
+		if job.Status == dto.JobQueued {
+			job.Status = dto.JobRunning
+		}
+	})
+	if !running {
+		return
+	}
+
+	response, err := s.hotelService.SearchHotels(ctx, batch.params)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		slog.ErrorContext(ctx, "search job batch failed", slog.String("jobId", batch.jobID), slog.String("error", err.Error()))
+	}
+
+	s.store.Update(batch.jobID, func(job *dto.SearchJob) {
+		job.BatchesDone++
+		if err != nil {
+			job.BatchesFailed++
+			job.Warnings = append(slices.Clip(job.Warnings), fmt.Sprintf("search of %d hotels failed: %v", len(batch.params.HotelIDs), err))
+		} else {
+			// Copy before merging, so a job read earlier keeps its own data
+			job.BatchData = slices.Clone(job.BatchData)
+			job.BatchData[batch.index] = response.HotelPrices
+			job.Data = []dto.HotelPrice{}
+			for _, prices := range job.BatchData {
+				job.Data = append(job.Data, prices...)
+			}
+			sortHotelPrices(job.Data, batch.params.Sort)
+			job.Warnings = append(slices.Clip(job.Warnings), response.Warnings...)
+		}
+
+		job.Progress = float64(job.BatchesDone) / float64(job.Batches)
+		if job.BatchesDone == job.Batches {
+			job.Status = dto.JobCompleted
+			if job.BatchesFailed == job.Batches {
+				job.Status = dto.JobFailed
+			}
+		}
+	})
+}
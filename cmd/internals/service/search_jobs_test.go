@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+// hotelServiceFunc adapts a function to the HotelService interface
+type hotelServiceFunc func(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error)
+
+func (f hotelServiceFunc) SearchHotels(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+	return f(ctx, params)
+}
+
+// pricePerHotel prices each hotel at its numeric ID and fails the batches with a hotel in failOn
+func pricePerHotel(failOn ...string) HotelService {
+	return hotelServiceFunc(func(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+		var response dto.HotelSearchServiceResponse
+		for _, id := range params.HotelIDs {
+			if slices.Contains(failOn, id) {
+				return dto.HotelSearchServiceResponse{}, errors.New("supplier error")
+			}
+
+			price, _ := strconv.ParseFloat(id, 64)
+			response.HotelPrices = append(response.HotelPrices, dto.HotelPrice{HotelID: id, Currency: params.Currency, Price: price})
+		}
+		return response, nil
+	})
+}
+
+func jobsConfig(batchSize, queueSize int) config.SearchConfig {
+	return config.SearchConfig{JobWorkers: 2, JobBatchSize: batchSize, JobQueueSize: queueSize, MaxJobs: 10, JobTTL: config.Duration{Duration: time.Hour}}
+}
+
+// waitForJob polls the job until every batch was searched
+func waitForJob(t *testing.T, jobService SearchJobService, ctx context.Context, id string) dto.SearchJob {
+	var job dto.SearchJob
+	assert.Eventually(t, func() bool {
+		job, _ = jobService.Job(ctx, id)
+		return job.BatchesDone == job.Batches
+	}, time.Second, 5*time.Millisecond)
+
+	return job
+}
+
+func TestSearchJobService(t *testing.T) {
+	tests := []struct {
+		name            string
+		hotelIDs        []string
+		failOn          []string
+		expectedStatus  string
+		expectedBatches int
+		expectedFailed  int
+		expectedHotels  []string
+	}{
+		{
+			name:            "Batched and merged in order",
+			hotelIDs:        []string{"50", "10", "40", "20", "30"},
+			expectedStatus:  dto.JobCompleted,
+			expectedBatches: 3,
+			expectedHotels:  []string{"10", "20", "30", "40", "50"},
+		},
+		{
+			name:            "Failed batch keeps the others",
+			hotelIDs:        []string{"50", "10", "40", "20"},
+			failOn:          []string{"40"},
+			expectedStatus:  dto.JobCompleted,
+			expectedBatches: 2,
+			expectedFailed:  1,
+			expectedHotels:  []string{"10", "50"},
+		},
+		{
+			name:            "Every batch failed",
+			hotelIDs:        []string{"50", "10"},
+			failOn:          []string{"10"},
+			expectedStatus:  dto.JobFailed,
+			expectedBatches: 1,
+			expectedFailed:  1,
+			expectedHotels:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			jobService := NewSearchJobService(pricePerHotel(tt.failOn...), NewMemoryJobStore(10), jobsConfig(2, 10))
+			go jobService.Run(ctx)
+
+			submitted, err := jobService.Submit(ctx, dto.HotelSearchServiceParams{HotelIDs: tt.hotelIDs, Currency: "EUR", Sort: dto.SortPriceAsc})
+			assert.NoError(t, err)
+			assert.Equal(t, dto.JobQueued, submitted.Status)
+			assert.Equal(t, tt.expectedBatches, submitted.Batches)
+
+			job := waitForJob(t, jobService, ctx, submitted.ID)
+
+			assert.Equal(t, tt.expectedStatus, job.Status)
+			assert.Equal(t, 1.0, job.Progress)
+			assert.Equal(t, tt.expectedFailed, job.BatchesFailed)
+			assert.Len(t, job.Warnings, tt.expectedFailed)
+
+			hotels := []string{}
+			for _, price := range job.Data {
+				hotels = append(hotels, price.HotelID)
+			}
+			assert.Equal(t, tt.expectedHotels, hotels)
+		})
+	}
+}
+
+func TestSearchJobService_PartialResults(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	release := make(chan struct{})
+	hotelService := hotelServiceFunc(func(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+		if params.HotelIDs[0] == "2" {
+			<-release
+		}
+		return pricePerHotel().SearchHotels(ctx, params)
+	})
+
+	cfg := jobsConfig(1, 10)
+	cfg.JobWorkers = 1
+	jobService := NewSearchJobService(hotelService, NewMemoryJobStore(10), cfg)
+	go jobService.Run(ctx)
+
+	submitted, err := jobService.Submit(ctx, dto.HotelSearchServiceParams{HotelIDs: []string{"1", "2"}})
+	assert.NoError(t, err)
+
+	var job dto.SearchJob
+	assert.Eventually(t, func() bool {
+		job, _ = jobService.Job(ctx, submitted.ID)
+		return job.BatchesDone == 1
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, dto.JobRunning, job.Status)
+	assert.Equal(t, 0.5, job.Progress)
+	assert.Len(t, job.Data, 1)
+
+	close(release)
+	job = waitForJob(t, jobService, ctx, submitted.ID)
+	assert.Equal(t, dto.JobCompleted, job.Status)
+	assert.Len(t, job.Data, 2)
+}
+
+func TestSearchJobService_RequestedOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first batch finishes last
+	release := make(chan struct{})
+	hotelService := hotelServiceFunc(func(ctx context.Context, params dto.HotelSearchServiceParams) (dto.HotelSearchServiceResponse, error) {
+		if params.HotelIDs[0] == "30" {
+			<-release
+		}
+		return pricePerHotel().SearchHotels(ctx, params)
+	})
+
+	jobService := NewSearchJobService(hotelService, NewMemoryJobStore(10), jobsConfig(1, 10))
+	go jobService.Run(ctx)
+
+	submitted, err := jobService.Submit(ctx, dto.HotelSearchServiceParams{HotelIDs: []string{"30", "10", "20"}})
+	assert.NoError(t, err)
+
+	var job dto.SearchJob
+	assert.Eventually(t, func() bool {
+		job, _ = jobService.Job(ctx, submitted.ID)
+		return job.BatchesDone == 2
+	}, time.Second, 5*time.Millisecond)
+
+	hotelIDs := func(job dto.SearchJob) []string {
+		ids := []string{}
+		for _, price := range job.Data {
+			ids = append(ids, price.HotelID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"10", "20"}, hotelIDs(job))
+
+	close(release)
+	job = waitForJob(t, jobService, ctx, submitted.ID)
+	assert.Equal(t, []string{"30", "10", "20"}, hotelIDs(job))
+}
+
+func TestSearchJobService_Tenant(t *testing.T) {
+	jobService := NewSearchJobService(pricePerHotel(), NewMemoryJobStore(10), jobsConfig(2, 10))
+
+	acme := auth.WithTenant(context.Background(), auth.Tenant{ID: "acme"})
+	submitted, err := jobService.Submit(acme, dto.HotelSearchServiceParams{HotelIDs: []string{"1"}})
+	assert.NoError(t, err)
+
+	_, err = jobService.Job(acme, submitted.ID)
+	assert.NoError(t, err)
+
+	other := auth.WithTenant(context.Background(), auth.Tenant{ID: "other"})
+	_, err = jobService.Job(other, submitted.ID)
+	assert.ErrorIs(t, err, ErrJobNotFound)
+}
+
+func TestSearchJobService_QueueFull(t *testing.T) {
+	jobService := NewSearchJobService(pricePerHotel(), NewMemoryJobStore(10), jobsConfig(1, 2))
+
+	_, err := jobService.Submit(context.Background(), dto.HotelSearchServiceParams{HotelIDs: []string{"1", "2", "3"}})
+	assert.ErrorIs(t, err, ErrJobsBusy)
+
+	// The queue keeps its room for a job that fits
+	_, err = jobService.Submit(context.Background(), dto.HotelSearchServiceParams{HotelIDs: []string{"1", "2"}})
+	assert.NoError(t, err)
+}
+
+func TestMemoryJobStore(t *testing.T) {
+	now := time.Date(2030, 6, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryJobStore(2)
+	store.now = func() time.Time { return now }
+
+	assert.NoError(t, store.Create(dto.SearchJob{ID: "short", ExpiresAt: now.Add(time.Minute)}))
+	assert.NoError(t, store.Create(dto.SearchJob{ID: "long", ExpiresAt: now.Add(time.Hour)}))
+	assert.ErrorIs(t, store.Create(dto.SearchJob{ID: "third", ExpiresAt: now.Add(time.Hour)}), ErrJobsBusy)
+
+	assert.True(t, store.Update("short", func(job *dto.SearchJob) { job.Status = dto.JobRunning }))
+	job, ok := store.Get("short")
+	assert.True(t, ok)
+	assert.Equal(t, dto.JobRunning, job.Status)
+
+	now = now.Add(2 * time.Minute)
+
+	_, ok = store.Get("short")
+	assert.False(t, ok)
+	assert.False(t, store.Update("short", func(job *dto.SearchJob) {}))
+
+	// Expired jobs make room for new ones
+	assert.NoError(t, store.Create(dto.SearchJob{ID: "third", ExpiresAt: now.Add(time.Hour)}))
+	_, ok = store.Get("long")
+	assert.True(t, ok)
+}
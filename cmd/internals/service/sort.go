@@ -0,0 +1,52 @@
+package service
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/dto"
+)
+
+// sortHotelPrices orders the prices in place; hotels without a distance come last in distance order and
+// ties keep the supplier order
+func sortHotelPrices(prices []dto.HotelPrice, order dto.SortOrder) {
+	var less func(a, b dto.HotelPrice) bool
+
+	switch order {
+	case dto.SortPriceAsc:
+		less = func(a, b dto.HotelPrice) bool { return a.Price < b.Price }
+	case dto.SortPriceDesc:
+		less = func(a, b dto.HotelPrice) bool { return a.Price > b.Price }
+	case dto.SortHotelID:
+		less = func(a, b dto.HotelPrice) bool { return hotelIDLess(a.HotelID, b.HotelID) }
+	case dto.SortName:
+		less = func(a, b dto.HotelPrice) bool { return a.Name < b.Name }
+	case dto.SortDistance:
+		less = func(a, b dto.HotelPrice) bool {
+			if a.Distance == nil || b.Distance == nil {
+				return a.Distance != nil && b.Distance == nil
+			}
+			return *a.Distance < *b.Distance
+		}
+	default:
+		return
+	}
+
+	sort.SliceStable(prices, func(i, j int) bool { return less(prices[i], prices[j]) })
+}
+
+// hotelIDLess orders liteAPI hotel IDs. Without a mapping file they are the numeric Hotelbeds codes, which
+// compare numerically; mapped IDs are opaque strings and compare as text, after any numeric ID, so the
+// order stays consistent when both kinds are mixed.
+func hotelIDLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a < b
+	}
+}
@@ -6,6 +6,11 @@ const (
 	HeaderSignature      = "X-Signature"
 	HeaderAccept         = "Accept"
 	HeaderAcceptEncoding = "Accept-Encoding"
+	HeaderRequestID      = "X-Request-ID"
+	HeaderLiteAPIKey     = "X-API-Key"
+	HeaderRetryAfter     = "Retry-After"
+
+	HeaderSupplierConfig = "x-liteapi-supplier-config"
 
 	ValueApplicationJSON = "application/json"
 	ValueGzip            = "gzip"
@@ -1,16 +1,161 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/auth"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/client"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/config"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/health"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/idmap"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/logging"
 	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/router"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/server"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/service"
+	"github.com/mjmhtjain/nuitee-mohit-jain/cmd/internals/telemetry"
 )
 
 func main() {
-	router := router.NewRouter().
+	// Load and validate the configuration; refuse to start on invalid or missing settings
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	// Setup structured logging first so startup failures are logged too
+	level, _ := logging.ParseLevel(cfg.Logging.Level)
+	slog.SetDefault(logging.NewLogger(os.Stdout, level))
+
+	// Stop on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Setup tracing before any handler is registered
+	shutdownTracing, err := telemetry.SetupTracing(ctx, telemetry.TracingConfig{
+		Exporter:    cfg.Tracing.Exporter,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
+	if err != nil {
+		slog.Error("failed to setup tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Build the dependency graph
+	// Searches and content syncs share one limiter, so the supplier caps hold across both
+	supplierLimiter := client.NewSupplierLimiter(cfg.HotelBeds)
+	breaker := client.NewCircuitBreaker(cfg.CircuitBreaker)
+	hotelBedsClient := client.NewRecordingClient(client.NewHotelBedsClient(cfg.HotelBeds, breaker, supplierLimiter), cfg.Recorder)
+	if cfg.Recorder.Mode != config.RecorderModePassthrough {
+		slog.Warn("hotelbeds recorder enabled", slog.String("mode", cfg.Recorder.Mode), slog.String("dir", cfg.Recorder.Dir))
+	}
+	if cfg.Search.CursorSecret == "" {
+		slog.Warn("no pagination cursor secret configured, cursors will not survive a restart")
+	}
+	// Without a mapping file the Hotelbeds codes are used as liteAPI hotel IDs
+	var mapper idmap.Mapper
+	if cfg.IDMapping.File != "" {
+		fileMapper, err := idmap.NewFileMapper(cfg.IDMapping.File)
+		if err != nil {
+			slog.Error("failed to load id mapping", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		slog.Info("id mapping loaded", slog.Int("hotels", fileMapper.Len()))
+		go fileMapper.Watch(ctx, cfg.IDMapping.ReloadInterval.Duration)
+		mapper = fileMapper
+	}
+
+	// Without historical rates, past dates are converted with the current rate table; the history is
+	// loaded again every reload interval, which keeps the FX readiness check fresh
+	var rateHistory service.RateStore
+	if cfg.Currency.HistoryDir != "" {
+		rateHistory = service.NewRateStore()
+		rates, err := service.LoadRateFiles(rateHistory, cfg.Currency.HistoryDir)
+		if err != nil {
+			slog.Error("failed to load historical rates", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		slog.Info("historical rates loaded", slog.Int("rates", rates))
+		go service.WatchRateFiles(ctx, rateHistory, cfg.Currency.HistoryDir, cfg.Currency.HistoryReloadInterval.Duration)
+	}
+
+	currencyService := service.NewCurrencyService(cfg.Currency, rateHistory)
+	hotelService := service.NewHotelService(hotelBedsClient, currencyService, mapper, cfg.HotelBeds.Currency)
+
+	// Hotel content has its own circuit breaker so a Content API outage never blocks searches
+	contentStore, err := service.NewContentStore(cfg.Content.StoreFile)
+	if err != nil {
+		slog.Error("failed to load content store", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+	contentClient := client.NewRecordingContentClient(client.NewHotelBedsContentClient(cfg.HotelBeds, cfg.Content, client.NewCircuitBreaker(cfg.CircuitBreaker), supplierLimiter), cfg.Recorder)
+	contentService := service.NewContentService(contentClient, contentStore, mapper, cfg.Content)
+	if cfg.Content.SyncEnabled {
+		go service.RunContentSync(ctx, contentService, cfg.Content.SyncInterval.Duration)
+	}
+
+	checks := []health.Check{
+		health.ConfigCheck(cfg),
+		health.FXRatesCheck(currencyService, cfg.Health.FXMaxAge.Duration),
+		health.CircuitBreakerCheck(breaker),
+	}
+	// Replayed fixtures need no supplier credentials
+	if cfg.Recorder.Mode != config.RecorderModeReplay {
+		checks = append(checks, health.CredentialsCheck(cfg.HotelBeds))
+	}
+
+	healthChecker := health.NewChecker(
+		checks,
+		[]health.Check{
+			health.Cached(health.SupplierCheck(hotelBedsClient), cfg.Health.DeepCheckTTL.Duration, cfg.Health.DeepCheckTimeout.Duration),
+		},
+	)
+
+	var keyStore auth.KeyStore
+	if cfg.Auth.Enabled {
+		keyStore, err = auth.NewFileKeyStore(cfg.Auth.KeysFile)
+		if err != nil {
+			slog.Error("failed to load keystore", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("API key authentication is disabled")
+	}
+
+	// Search jobs are searched in the background until shutdown, with the same enrichment as /hotels
+	enrichedHotelService := service.NewContentEnrichedHotelService(hotelService, contentStore, mapper)
+	searchJobService := service.NewSearchJobService(enrichedHotelService, service.NewMemoryJobStore(cfg.Search.MaxJobs), cfg.Search)
+	go searchJobService.Run(ctx)
+
+	router := router.NewRouter(router.Options{
+		Config:                cfg,
+		HotelService:          enrichedHotelService,
+		FlexibleSearchService: service.NewFlexibleSearchService(hotelService, cfg.Search),
+		SearchPaginator:       service.NewSearchPaginator(cfg.Search),
+		ContentService:        contentService,
+		CurrencyService:       currencyService,
+		SearchJobService:      searchJobService,
+		HealthChecker:         healthChecker,
+		KeyStore:              keyStore,
+		Middleware:            router.DefaultMiddleware(),
+	}).
 		Setup()
 
-	// Start the server on port 8080
-	router.Run(fmt.Sprintf(":%s", os.Getenv("PORT")))
+	srv := server.New(cfg.Server, router)
+
+	// Save content and flush pending spans once in-flight requests have drained; logs are written unbuffered
+	srv.OnShutdown(func(context.Context) error {
+		return contentStore.Save()
+	})
+	srv.OnShutdown(shutdownTracing)
+
+	if err := srv.ListenAndServe(ctx); err != nil {
+		slog.Error("server error", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 }